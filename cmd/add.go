@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	fromFlag   string
+	toFlag     string
+	pauseFlags []string
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Record a session that already happened",
+	Long: `Record work after the fact by giving the session's start and end time.
+
+Pauses are given as "start..end" ranges and are cut out of the session,
+so only the remaining time counts as active. Times without a date are
+taken to be on the same day as --from. The session is rejected if it
+overlaps time that is already logged.
+
+Examples:
+  worklogger add --task "Code review" --from 09:00 --to 11:30
+  worklogger add -t "Release" --from "2026-10-17 13:00" --to 18:00 --pause 15:00..15:20
+  worklogger add -t "Standup" --from "yesterday 09:30" --to 09:45`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if taskFlag == "" {
+			fmt.Println("⚠️  No task provided. Use --task or -t to specify one.")
+			return
+		}
+
+		if fromFlag == "" || toFlag == "" {
+			fmt.Println("⚠️  Both --from and --to are required.")
+			return
+		}
+
+		if err := validateModeAndFlags(); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		now := time.Now()
+
+		from, err := parseAt(fromFlag, now)
+		if err != nil {
+			fmt.Printf("⚠️  --from: %s\n", err.Error())
+			return
+		}
+
+		to, err := parseTime(toFlag, from)
+		if err != nil {
+			fmt.Printf("⚠️  --to: %s\n", err.Error())
+			return
+		}
+
+		if !to.After(from) {
+			fmt.Println("⚠️  --to must be after --from.")
+			return
+		}
+
+		if to.After(now) {
+			fmt.Println("⚠️  --to can't be in the future. Use `worklogger start` for ongoing work.")
+			return
+		}
+
+		intervals, err := splitByPauses(from, to, pauseFlags)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if estimateFlag < 0 {
+			fmt.Println("⚠️  --estimate can't be negative.")
+			return
+		}
+
+		plan, err := loadTaskPlan()
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		task := &data.Task{
+			Description: taskFlag,
+		}
+
+		session := &data.TaskSession{
+			StartedAt: from,
+			EndedAt:   &to,
+			Mode:      getSessionMode(),
+			Notes:     notesFlag,
+			Synced:    false,
+			Workspace: workspace,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		if err := plan.apply(tx, task); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if err := models.CreateRetroactiveTask(tx, task, session, intervals); err != nil {
+			if errors.Is(err, data.ErrOverlappingInterval) {
+				fmt.Println("⚠️  This session overlaps time that is already logged.")
+				return
+			}
+			cmd.PrintErr(fmt.Errorf("failed to record session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if nonBillableFlag {
+			if err := models.TaskSessions.SetBillableTX(tx, session.ID, false); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark session non-billable: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if len(tagFlags) > 0 {
+			if err := models.SessionTags.Create(tx, session.ID, tagFlags); err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+		}
+
+		if len(kpiFlags) > 0 {
+			if err := models.SessionKPI.Create(tx, session.ID, kpiFlags); err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		var active time.Duration
+		for _, tsi := range intervals {
+			active += tsi.EndTime.Sub(tsi.StartTime)
+		}
+		total := to.Sub(from)
+
+		fmt.Printf("Session #%d recorded for task: %s\n", session.ID, task.Description)
+		fmt.Printf("   %s → %s\n", from.In(data.Location()).Format("2006-01-02 15:04"), to.In(data.Location()).Format("2006-01-02 15:04"))
+		fmt.Printf("  ⏱️  Total:  %v\n", total)
+		fmt.Printf("  🟢 Active: %v\n", active)
+		fmt.Printf("  🛑 Paused: %v\n", total-active)
+
+		if session.Mode == "org" {
+			fmt.Printf("   Mode: %s\n", session.Mode)
+		}
+		if len(tagFlags) > 0 {
+			fmt.Printf("   Tags: %s\n", strings.Join(tagFlags, ", "))
+		}
+		if len(kpiFlags) > 0 {
+			fmt.Printf("   KPIs: %s\n", strings.Join(kpiFlags, ", "))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+
+	addCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task description")
+	addCmd.Flags().StringVar(&fromFlag, "from", "", "When the session started")
+	addCmd.Flags().StringVar(&toFlag, "to", "", "When the session ended")
+	addCmd.Flags().StringArrayVar(&pauseFlags, "pause", nil, "Pause within the session as start..end (repeatable)")
+
+	addCmd.Flags().StringVar(&modeFlag, "mode", "", "Session mode: personal or org")
+	addCmd.Flags().StringSliceVar(&tagFlags, "tag", nil, "Tags to associate with the session")
+	addCmd.Flags().StringSliceVar(&kpiFlags, "kpi", nil, "KPIs to associate with the session (required for org mode)")
+	addCmd.Flags().StringVar(&notesFlag, "notes", "", "Notes for this session")
+	addCmd.Flags().BoolVar(&nonBillableFlag, "non-billable", false, "Keep this session off invoices")
+	addCmd.Flags().DurationVar(&estimateFlag, "estimate", 0, "How long the task should take, e.g. 3h or 90m")
+	addCmd.Flags().StringVar(&parentFlag, "parent", "", "Parent task ID or description, making this task a subtask")
+}
+
+// splitByPauses turns a from/to range and a list of pauses into the active
+// intervals between them.
+func splitByPauses(from, to time.Time, pauses []string) ([]*data.TaskSessionInterval, error) {
+	var gaps []*data.TaskSessionInterval
+	for _, p := range pauses {
+		start, end, err := parseTimeRange(p, from)
+		if err != nil {
+			return nil, fmt.Errorf("--pause: %w", err)
+		}
+		gaps = append(gaps, &data.TaskSessionInterval{StartTime: start, EndTime: &end})
+	}
+
+	if err := data.ValidateIntervals(from, to, gaps); err != nil {
+		return nil, fmt.Errorf("--pause: %w", err)
+	}
+
+	var intervals []*data.TaskSessionInterval
+	cursor := from
+	for _, gap := range gaps {
+		if gap.StartTime.After(cursor) {
+			end := gap.StartTime
+			intervals = append(intervals, &data.TaskSessionInterval{StartTime: cursor, EndTime: &end})
+		}
+		cursor = *gap.EndTime
+	}
+
+	if to.After(cursor) {
+		end := to
+		intervals = append(intervals, &data.TaskSessionInterval{StartTime: cursor, EndTime: &end})
+	}
+
+	if len(intervals) == 0 {
+		return nil, errors.New("pauses cover the whole session")
+	}
+
+	return intervals, nil
+}
@@ -0,0 +1,493 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+	"gopkg.in/yaml.v3"
+)
+
+// repoConfigFile holds settings for one repository. They override the user's
+// ~/.worklogger.yaml, and command-line flags override both.
+const repoConfigFile = ".worklogger/config.yaml"
+
+const (
+	kindString   = "string"
+	kindList     = "list"
+	kindBool     = "bool"
+	kindDuration = "duration"
+)
+
+// configKey describes a setting worklogger reads.
+type configKey struct {
+	name  string
+	kind  string
+	help  string
+	check func(string) error
+}
+
+var configKeys = []configKey{
+	{"session.mode", kindString, "Mode of new sessions: personal or org", oneOf("personal", "org")},
+	{"session.tags", kindList, "Tags for sessions started without --tag", nil},
+	{"session.kpis", kindList, "KPIs for sessions started without --kpi", nil},
+	{"org.required_kpis", kindList, "KPIs every org-mode session must have", nil},
+	{"timezone", kindString, "IANA timezone to report in (default: the system timezone)", checkTimezone},
+	{"server.address", kindString, "Address 'worklogger studio' listens on", nil},
+	{"rounding.step", kindDuration, "Round reported time to this step, e.g. 6m or 15m (default: exact)", nil},
+	{"rounding.mode", kindString, "Round reported time up, down or to the nearest step", oneOf("", data.RoundUp, data.RoundDown, data.RoundNearest)},
+	{"rounding.per", kindString, "Round each interval, session or day before adding up", oneOf("", data.RoundPerInterval, data.RoundPerSession, data.RoundPerDay)},
+	{"catalog.strict", kindBool, "Only accept tags and KPIs already in the catalog", nil},
+	{"stale.threshold", kindDuration, "How long an interval may run before it counts as stale", nil},
+	{"stale.day_end", kindString, "Clock time after which a running session is stale, e.g. 18:00", nil},
+	{"stale.auto_cap", kindString, "End stale sessions without asking: last-commit, default or off", oneOf("", autoCapLastCommit, autoCapDefault, autoCapOff)},
+	{"schedule.days", kindList, "Working days, e.g. mon,tue,wed,thu,fri", nil},
+	{"schedule.start", kindString, "Start of the working day, e.g. 09:00", nil},
+	{"schedule.end", kindString, "End of the working day, e.g. 17:30", nil},
+	{"schedule.lunch", kindString, "Lunch break, e.g. 12:30-13:30", nil},
+	{"schedule.at_end", kindString, "What happens to a running session when the day ends: stop, pause or off", oneOf(atEndStop, atEndPause, atEndOff)},
+	{"schedule.remind_every", kindDuration, "How often to remind you when nothing runs during working hours", nil},
+	{"notify.command", kindString, "Command reminders are sent through", nil},
+	{"invoice.currency", kindString, "Currency invoices are written in, e.g. USD", nil},
+	{"invoice.prefix", kindString, "Start of invoice numbers, e.g. INV-", nil},
+	{"invoice.templates", kindString, "Directory with invoice.<md|html|csv>.tmpl overrides", nil},
+}
+
+var configRepoFlag bool
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and change settings",
+	Long: `Settings are read from ~/.worklogger.yaml (or --config) and from
+.worklogger/config.yaml in the repository. Repository settings override
+your own, and command-line flags override both.
+
+Example:
+  worklogger config list
+  worklogger config set session.tags backend,api --repo
+  worklogger config set org.required_kpis quality
+  worklogger config get timezone
+  worklogger config edit`,
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show every setting, its value and where it comes from",
+	Run: func(cmd *cobra.Command, args []string) {
+		user, repo := loadConfigFile(userConfigFile()), loadConfigFile(repoConfigFile)
+
+		fmt.Printf("User:       %s\n", userConfigFile())
+		fmt.Printf("Repository: %s\n\n", repoConfigFile)
+
+		fmt.Printf("%-22s  %-30s  %s\n", "KEY", "VALUE", "SOURCE")
+		for _, k := range configKeys {
+			source := "default"
+			switch {
+			case repo.IsSet(k.name):
+				source = "repository"
+			case user.IsSet(k.name):
+				source = "user"
+			}
+
+			value := formatConfigValue(k, viper.Get(k.name))
+			fmt.Printf("%-22s  %-30s  %s\n", k.name, truncate(value, 30), source)
+		}
+
+		for _, problem := range checkConfig(viper.GetViper()) {
+			fmt.Printf("⚠️  %s\n", problem)
+		}
+	},
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		fmt.Println(formatConfigValue(k, viper.Get(k.name)))
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in your config, or the repository's with --repo",
+	Long: `Change a setting. Lists are comma-separated; an empty value clears the
+setting. Use --repo to change it for this repository only.
+
+Only the setting's line in the file changes; comments and the other settings
+stay as they are.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		k, err := lookupConfigKey(args[0])
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		value, err := parseConfigValue(k, args[1])
+		if err != nil {
+			fmt.Printf("⚠️  %s: %s\n", k.name, err.Error())
+			return
+		}
+
+		path := userConfigFile()
+		if configRepoFlag {
+			path = repoConfigFile
+		}
+
+		if err := writeConfigValue(path, k.name, value); err != nil {
+			cmd.PrintErrf("failed to save %s: %v\n", path, err)
+			return
+		}
+
+		if value == nil {
+			fmt.Printf("⚙️  Cleared %s (in %s)\n", k.name, path)
+			return
+		}
+		fmt.Printf("⚙️  %s = %s (in %s)\n", k.name, formatConfigValue(k, value), path)
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open your config, or the repository's with --repo, in $EDITOR",
+	Run: func(cmd *cobra.Command, args []string) {
+		path := userConfigFile()
+		if configRepoFlag {
+			path = repoConfigFile
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			cmd.PrintErrf("failed to create %s: %v\n", filepath.Dir(path), err)
+			return
+		}
+
+		file, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			cmd.PrintErrf("failed to open %s: %v\n", path, err)
+			return
+		}
+		file.Close()
+
+		editor := os.Getenv("VISUAL")
+		if editor == "" {
+			editor = os.Getenv("EDITOR")
+		}
+		if editor == "" {
+			editor = "vi"
+		}
+
+		fields := strings.Fields(editor)
+		c := exec.Command(fields[0], append(fields[1:], path)...)
+		c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := c.Run(); err != nil {
+			cmd.PrintErrf("editor failed: %v\n", err)
+			return
+		}
+
+		edited := viper.New()
+		edited.SetConfigFile(path)
+		edited.SetConfigType("yaml")
+		if err := edited.ReadInConfig(); err != nil {
+			fmt.Printf("⚠️  %s can't be read: %v\n", path, err)
+			return
+		}
+
+		problems := checkConfig(edited)
+		for _, problem := range problems {
+			fmt.Printf("⚠️  %s\n", problem)
+		}
+		if len(problems) == 0 {
+			fmt.Printf("⚙️  Saved %s\n", path)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd, configGetCmd, configSetCmd, configEditCmd)
+
+	configSetCmd.Flags().BoolVar(&configRepoFlag, "repo", false, "Change the repository's settings in "+repoConfigFile)
+	configEditCmd.Flags().BoolVar(&configRepoFlag, "repo", false, "Edit the repository's settings in "+repoConfigFile)
+
+	var keys strings.Builder
+	for _, k := range configKeys {
+		fmt.Fprintf(&keys, "\n  %-22s  %s", k.name, k.help)
+	}
+	configCmd.Long += "\n\nSettings:" + keys.String()
+
+	viper.SetDefault("session.mode", "personal")
+	viper.SetDefault("server.address", ":3001")
+	viper.SetDefault("stale.threshold", 12*time.Hour)
+}
+
+// userConfigFile is the config file given with --config, or
+// ~/.worklogger.yaml.
+func userConfigFile() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".worklogger.yaml"
+	}
+	return filepath.Join(home, ".worklogger.yaml")
+}
+
+// mergeRepoConfig layers the repository's settings over the user's.
+func mergeRepoConfig() {
+	if _, err := os.Stat(repoConfigFile); err != nil {
+		return
+	}
+
+	viper.SetConfigFile(repoConfigFile)
+	viper.SetConfigType("yaml")
+	if err := viper.MergeInConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ignoring %s: %v\n", repoConfigFile, err)
+	}
+}
+
+// loadConfigFile reads one config file on its own. A missing or broken file
+// reads as empty.
+func loadConfigFile(path string) *viper.Viper {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	_ = v.ReadInConfig()
+	return v
+}
+
+// writeConfigValue sets key in the config file at path, or removes it when
+// value is nil. Only that key's line changes: other settings, their order
+// and comments are kept.
+func writeConfigValue(path, key string, value any) error {
+	content, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+
+	section := doc.Content[0]
+	if section.Kind != yaml.MappingNode {
+		return errors.New("the file doesn't hold a list of settings")
+	}
+
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		next := yamlValue(section, part)
+		if next == nil {
+			if value == nil {
+				return nil
+			}
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, next)
+		}
+		if next.Kind != yaml.MappingNode {
+			*next = yaml.Node{Kind: yaml.MappingNode, HeadComment: next.HeadComment, LineComment: next.LineComment}
+		}
+		section = next
+	}
+
+	name := parts[len(parts)-1]
+	if value == nil {
+		for i := 0; i+1 < len(section.Content); i += 2 {
+			if strings.EqualFold(section.Content[i].Value, name) {
+				section.Content = append(section.Content[:i], section.Content[i+2:]...)
+				break
+			}
+		}
+	} else {
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return err
+		}
+		if existing := yamlValue(section, name); existing != nil {
+			node.LineComment = existing.LineComment
+			*existing = node
+		} else {
+			section.Content = append(section.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, &node)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// yamlValue returns the value of key in a YAML mapping, matching keys the
+// way viper does, without case.
+func yamlValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func lookupConfigKey(name string) (configKey, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, k := range configKeys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.name
+	}
+	return configKey{}, fmt.Errorf("unknown setting %q.%s See `worklogger config list`", name, didYouMean(data.Suggest(name, names)))
+}
+
+// parseConfigValue turns a command-line value into what the setting holds.
+// An empty value is nil, which clears the setting.
+func parseConfigValue(k configKey, value string) (any, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	switch k.kind {
+	case kindList:
+		return nonEmpty(strings.Split(value, ",")), nil
+	case kindBool:
+		return strconv.ParseBool(value)
+	case kindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		if k.check != nil {
+			if err := k.check(value); err != nil {
+				return nil, err
+			}
+		}
+		return value, nil
+	}
+}
+
+func formatConfigValue(k configKey, value any) string {
+	switch k.kind {
+	case kindList:
+		if list, ok := value.([]string); ok {
+			return strings.Join(list, ",")
+		}
+		if list, ok := value.([]any); ok {
+			parts := make([]string, len(list))
+			for i, v := range list {
+				parts[i] = fmt.Sprint(v)
+			}
+			return strings.Join(parts, ",")
+		}
+	case kindDuration:
+		if d, ok := value.(time.Duration); ok {
+			return d.String()
+		}
+	}
+
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// checkConfig reports settings whose values worklogger can't use.
+func checkConfig(v *viper.Viper) []string {
+	var problems []string
+
+	for _, k := range configKeys {
+		if !v.IsSet(k.name) {
+			continue
+		}
+
+		raw := v.GetString(k.name)
+		switch k.kind {
+		case kindBool:
+			if _, err := strconv.ParseBool(raw); err != nil && raw != "" {
+				problems = append(problems, fmt.Sprintf("%s: %q is not true or false", k.name, raw))
+			}
+		case kindDuration:
+			if _, err := time.ParseDuration(raw); err != nil && raw != "" {
+				problems = append(problems, fmt.Sprintf("%s: %v", k.name, err))
+			}
+		case kindString:
+			if k.check != nil {
+				if err := k.check(raw); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: %v", k.name, err))
+				}
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+func oneOf(values ...string) func(string) error {
+	return func(value string) error {
+		for _, v := range values {
+			if value == v {
+				return nil
+			}
+		}
+
+		var named []string
+		for _, v := range values {
+			if v != "" {
+				named = append(named, v)
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(named, ", "))
+	}
+}
+
+func checkTimezone(value string) error {
+	if value == "" {
+		return nil
+	}
+	_, err := time.LoadLocation(value)
+	return err
+}
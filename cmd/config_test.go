@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+func TestConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	user := filepath.Join(dir, "user.yaml")
+	writeFile(t, user, `
+session:
+  mode: org
+  tags: [frontend]
+server:
+  address: ":4000"
+invoice:
+  prefix: ME-
+`)
+	writeFile(t, repoConfigFile, `
+session:
+  tags: [backend]
+invoice:
+  prefix: ACME-
+`)
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.SetConfigFile(user)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	mergeRepoConfig()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("prefix", "", "")
+	flags.String("mode", "", "")
+	viper.BindPFlag("invoice.prefix", flags.Lookup("prefix"))
+	viper.BindPFlag("session.mode", flags.Lookup("mode"))
+	if err := flags.Parse([]string{"--prefix", "CLI-"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key  string
+		want any
+	}{
+		{key: "session.mode", want: "org"},
+		{key: "session.tags", want: []string{"backend"}},
+		{key: "server.address", want: ":4000"},
+		{key: "invoice.prefix", want: "CLI-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			var got any = viper.GetString(tt.key)
+			if _, ok := tt.want.([]string); ok {
+				got = viper.GetStringSlice(tt.key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConfigValue(t *testing.T) {
+	const settings = `# worklogger settings
+session:
+  mode: personal # default for new sessions
+  # every session gets these
+  tags:
+    - backend
+TimeZone: Europe/Berlin
+`
+
+	tests := []struct {
+		name  string
+		key   string
+		value any
+		want  string
+	}{
+		{
+			name: "change", key: "session.mode", value: "org",
+			want: `# worklogger settings
+session:
+  mode: org # default for new sessions
+  # every session gets these
+  tags:
+    - backend
+TimeZone: Europe/Berlin
+`,
+		},
+		{
+			name: "change a list", key: "session.tags", value: []string{"api", "web"},
+			want: `# worklogger settings
+session:
+  mode: personal # default for new sessions
+  # every session gets these
+  tags:
+    - api
+    - web
+TimeZone: Europe/Berlin
+`,
+		},
+		{
+			name: "any case", key: "timezone", value: "UTC",
+			want: `# worklogger settings
+session:
+  mode: personal # default for new sessions
+  # every session gets these
+  tags:
+    - backend
+TimeZone: UTC
+`,
+		},
+		{
+			name: "add", key: "rounding.step", value: "15m",
+			want: settings + `rounding:
+  step: 15m
+`,
+		},
+		{
+			name: "clear", key: "timezone", value: nil,
+			want: `# worklogger settings
+session:
+  mode: personal # default for new sessions
+  # every session gets these
+  tags:
+    - backend
+`,
+		},
+		{name: "clear a missing setting", key: "server.address", value: nil, want: settings},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeFile(t, path, settings)
+
+			if err := writeConfigValue(path, tt.key, tt.value); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteConfigValueNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".worklogger", "config.yaml")
+
+	if err := writeConfigValue(path, "session.tags", []string{"backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "session:\n  tags:\n    - backend\n"; string(got) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,429 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	editTaskFlag      string
+	editMoveToFlag    string
+	editModeFlag      string
+	editNotesFlag     string
+	editTagFlags      []string
+	editKPIFlags      []string
+	editStartFlag     string
+	editEndFlag       string
+	editIntervalFlags []string
+	editPauseFlags    []string
+	editBillableFlag  bool
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <session-id>",
+	Short: "Fix the details or times of a recorded session",
+	Long: `Change a session after it has been recorded.
+
+Run without flags to print the session and the IDs of its intervals.
+Times without a date are taken to be on the day the session started.
+Interval boundaries are changed with --interval <id>=<start>..<end>;
+leave the end empty to keep the latest interval of an active session open.
+A pause that wasn't recorded is cut out of its interval with
+--pause <start>..<end>.
+
+--task renames the session's task, for every session logged on it.
+--move-to moves just this session to another task, given by ID or
+description; a task that doesn't exist yet is created.
+
+--tag and --kpi replace the existing list; pass an empty value
+(--tag "") to clear it. All changes are validated and saved together.
+
+Examples:
+  worklogger edit 12
+  worklogger edit 12 --task "Fix login bug" --notes "Paired with Sam"
+  worklogger edit 12 --move-to 7
+  worklogger edit 12 --start 09:15 --end 17:40
+  worklogger edit 12 --pause 12:00..12:40
+  worklogger edit 12 --billable=false
+  worklogger edit 12 --interval 31=09:15..12:00 --interval 32=13:00..17:40`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("⚠️  Invalid session ID: %s\n", args[0])
+			return
+		}
+
+		ts, err := models.TaskSessions.GetByID(id)
+		if err != nil {
+			if errors.Is(err, data.ErrRecordNotFound) {
+				fmt.Printf("Session #%d not found.\n", id)
+				return
+			}
+			cmd.PrintErr(fmt.Errorf("failed to load session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		task, err := models.Tasks.Get(ts.TaskID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load task: %w", err))
+			fmt.Println()
+			return
+		}
+
+		intervals, err := models.TaskSessionIntervals.GetAllForSession(ts.ID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load intervals: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if !editFlagsChanged(cmd) {
+			printSessionDetails(cmd, task, ts, intervals)
+			return
+		}
+
+		previous := task
+		task, intervals, err = applySessionEdits(cmd, task, ts, intervals)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		if cmd.Flags().Changed("task") {
+			if err := models.Tasks.UpdateTx(tx, task); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to update task: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if task.ID == 0 {
+			if err := models.Tasks.CreateTx(tx, task); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to create task: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+		ts.TaskID = task.ID
+
+		if ts.EndedAt == nil && task.Status != data.TaskInProgress {
+			if err := models.Tasks.SetStatusTx(tx, task.ID, data.TaskInProgress); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark task in progress: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if err := models.UpdateSession(tx, ts, intervals); err != nil {
+			if errors.Is(err, data.ErrOverlappingInterval) {
+				fmt.Println("⚠️  The new times overlap time that is already logged.")
+				return
+			}
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if task.ID != previous.ID {
+			if err := models.Tasks.ReleaseTx(tx, previous.ID); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to update task #%d: %w", previous.ID, err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if cmd.Flags().Changed("tag") {
+			if err := models.SessionTags.Update(tx, ts.ID, nonEmpty(editTagFlags)); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to update tags: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if cmd.Flags().Changed("billable") {
+			if err := models.TaskSessions.SetBillableTX(tx, ts.ID, editBillableFlag); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to update billable flag: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if cmd.Flags().Changed("kpi") {
+			if err := models.SessionKPI.Update(tx, ts.ID, nonEmpty(editKPIFlags)); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to update KPIs: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		fmt.Printf("Session #%d updated.\n", ts.ID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+
+	editCmd.Flags().StringVarP(&editTaskFlag, "task", "t", "", "New task description")
+	editCmd.Flags().StringVar(&editMoveToFlag, "move-to", "", "Move the session to another task, by ID or description")
+	editCmd.Flags().StringVar(&editModeFlag, "mode", "", "Session mode: personal or org")
+	editCmd.Flags().StringVar(&editNotesFlag, "notes", "", "Notes for this session")
+	editCmd.Flags().StringSliceVar(&editTagFlags, "tag", nil, "Replace the session's tags")
+	editCmd.Flags().StringSliceVar(&editKPIFlags, "kpi", nil, "Replace the session's KPIs")
+	editCmd.Flags().StringVar(&editStartFlag, "start", "", "New session start time")
+	editCmd.Flags().StringVar(&editEndFlag, "end", "", "New session end time")
+	editCmd.Flags().StringArrayVar(&editIntervalFlags, "interval", nil, "Change an interval as <id>=<start>..<end> (repeatable)")
+	editCmd.Flags().StringArrayVar(&editPauseFlags, "pause", nil, "Add a pause that wasn't recorded as <start>..<end> (repeatable)")
+	editCmd.Flags().BoolVar(&editBillableFlag, "billable", true, "Whether the session can be invoiced (--billable=false to keep it off invoices)")
+
+	editCmd.MarkFlagsMutuallyExclusive("task", "move-to")
+}
+
+// editFlagsChanged reports whether any of edit's own flags were given.
+// Persistent flags such as --dsn don't make a run an edit.
+func editFlagsChanged(cmd *cobra.Command) bool {
+	changed := false
+	cmd.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			changed = true
+		}
+	})
+	return changed
+}
+
+// applySessionEdits copies the flag values onto the loaded records and
+// returns the session's task and intervals as edited. A new task or interval
+// has no ID yet; nothing is written to the database here.
+func applySessionEdits(cmd *cobra.Command, task *data.Task, ts *data.TaskSession, intervals []*data.TaskSessionInterval) (*data.Task, []*data.TaskSessionInterval, error) {
+	flags := cmd.Flags()
+
+	if flags.Changed("task") {
+		if strings.TrimSpace(editTaskFlag) == "" {
+			return nil, nil, errors.New("task description cannot be empty")
+		}
+		task.Description = editTaskFlag
+	}
+
+	if flags.Changed("move-to") {
+		if strings.TrimSpace(editMoveToFlag) == "" {
+			return nil, nil, errors.New("--move-to needs a task ID or description")
+		}
+
+		id, _ := strconv.Atoi(editMoveToFlag)
+		next, err := resolveTask(id, editMoveToFlag)
+		if err != nil {
+			return nil, nil, err
+		}
+		if next.ID == 0 {
+			// A new task stays where the old one was filed.
+			next.ProjectID = task.ProjectID
+			next.ParentID = task.ParentID
+		}
+		task = next
+	}
+
+	if flags.Changed("notes") {
+		ts.Notes = editNotesFlag
+	}
+
+	if flags.Changed("mode") {
+		if editModeFlag != "personal" && editModeFlag != "org" {
+			return nil, nil, fmt.Errorf("mode must be 'personal' or 'org', got: %s", editModeFlag)
+		}
+		ts.Mode = editModeFlag
+	}
+
+	var tags, kpis []string
+	if flags.Changed("tag") {
+		tags = nonEmpty(editTagFlags)
+	}
+	if flags.Changed("kpi") {
+		kpis = nonEmpty(editKPIFlags)
+	}
+	if err := checkCatalog(tags, kpis); err != nil {
+		return nil, nil, err
+	}
+
+	if ts.Mode == "org" {
+		kpis := nonEmpty(editKPIFlags)
+		if !flags.Changed("kpi") {
+			existing, err := models.SessionKPI.GetBySession(ts.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			kpis = existing
+		}
+		if len(kpis) == 0 {
+			return nil, nil, errors.New("organization mode requires at least one KPI (use --kpi)")
+		}
+		if err := checkOrgKPIs(ts.Mode, kpis); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	base := ts.StartedAt.In(data.Location())
+
+	if flags.Changed("start") {
+		start, err := parseTime(editStartFlag, base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--start: %w", err)
+		}
+		ts.StartedAt = start
+		base = start
+	}
+
+	if flags.Changed("end") {
+		if ts.EndedAt == nil {
+			return nil, nil, errors.New("the session is still active; stop it before setting --end")
+		}
+		end, err := parseTime(editEndFlag, base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--end: %w", err)
+		}
+		ts.EndedAt = &end
+	}
+
+	byID := make(map[int]*data.TaskSessionInterval, len(intervals))
+	for _, tsi := range intervals {
+		byID[tsi.ID] = tsi
+	}
+
+	for _, value := range editIntervalFlags {
+		idPart, rangePart, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, nil, fmt.Errorf("--interval: expected <id>=<start>..<end>, got %q", value)
+		}
+
+		intervalID, err := strconv.Atoi(strings.TrimSpace(idPart))
+		if err != nil {
+			return nil, nil, fmt.Errorf("--interval: invalid interval ID %q", idPart)
+		}
+
+		tsi, found := byID[intervalID]
+		if !found {
+			return nil, nil, fmt.Errorf("--interval: interval #%d does not belong to session #%d", intervalID, ts.ID)
+		}
+
+		startPart, endPart, ok := strings.Cut(rangePart, "..")
+		if !ok {
+			return nil, nil, fmt.Errorf("--interval: expected <id>=<start>..<end>, got %q", value)
+		}
+
+		start, err := parseTime(startPart, base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--interval: %w", err)
+		}
+		tsi.StartTime = start
+
+		if strings.TrimSpace(endPart) == "" {
+			tsi.EndTime = nil
+			continue
+		}
+
+		end, err := parseTime(endPart, start)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--interval: %w", err)
+		}
+		tsi.EndTime = &end
+	}
+
+	for _, value := range editPauseFlags {
+		start, end, err := parseTimeRange(value, base)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--pause: %w", err)
+		}
+		intervals, err = data.InsertPause(intervals, start, end)
+		if err != nil {
+			return nil, nil, fmt.Errorf("--pause: %w", err)
+		}
+	}
+
+	return task, intervals, nil
+}
+
+func printSessionDetails(cmd *cobra.Command, task *data.Task, ts *data.TaskSession, intervals []*data.TaskSessionInterval) {
+	const layout = "2006-01-02 15:04:05"
+
+	end := "ongoing"
+	if ts.EndedAt != nil {
+		end = ts.EndedAt.In(data.Location()).Format(layout)
+	}
+
+	fmt.Printf("Session #%d: %s\n", ts.ID, task.Description)
+	fmt.Printf("   Start: %s\n", ts.StartedAt.In(data.Location()).Format(layout))
+	fmt.Printf("   End:   %s\n", end)
+	fmt.Printf("   Mode:  %s\n", ts.Mode)
+	if !ts.Billable {
+		fmt.Println("   Not billable")
+	}
+	if ts.Workspace != "" {
+		fmt.Printf("   Project: %s\n", ts.Workspace)
+	}
+	if ts.Notes != "" {
+		fmt.Printf("   Notes: %s\n", ts.Notes)
+	}
+
+	tags, err := models.SessionTags.GetBySession(ts.ID)
+	if err != nil {
+		cmd.PrintErr(fmt.Errorf("failed to load tags: %w", err))
+		fmt.Println()
+		return
+	}
+	if len(tags) > 0 {
+		fmt.Printf("   Tags:  %s\n", strings.Join(tags, ", "))
+	}
+
+	kpis, err := models.SessionKPI.GetBySession(ts.ID)
+	if err != nil {
+		cmd.PrintErr(fmt.Errorf("failed to load KPIs: %w", err))
+		fmt.Println()
+		return
+	}
+	if len(kpis) > 0 {
+		fmt.Printf("   KPIs:  %s\n", strings.Join(kpis, ", "))
+	}
+
+	fmt.Println("   Intervals:")
+	for _, tsi := range intervals {
+		intervalEnd := "running"
+		if tsi.EndTime != nil {
+			intervalEnd = tsi.EndTime.In(data.Location()).Format(layout)
+		}
+		fmt.Printf("     #%d  %s → %s\n", tsi.ID, tsi.StartTime.In(data.Location()).Format(layout), intervalEnd)
+	}
+}
+
+// nonEmpty drops blank values so that an empty flag clears a list.
+func nonEmpty(values []string) []string {
+	var out []string
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,339 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+const (
+	hookName = "post-commit"
+
+	// hookMarker identifies a hook written by 'worklogger hooks install'.
+	hookMarker = "# worklogger post-commit hook"
+
+	// chainedHookSuffix is added to a hook that was in place before ours. It
+	// keeps running, before the commit is recorded.
+	chainedHookSuffix = ".worklogger-orig"
+)
+
+var hooksForceFlag bool
+
+// hooksCmd represents the hooks command
+var hooksCmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage the git hook that records commits",
+	Long: `Install a git post-commit hook that records every commit on the
+repository's active session.
+
+The hook goes where git runs hooks from, following core.hooksPath and
+linked worktrees. A post-commit hook that is already there is kept and
+still runs first; uninstalling puts it back.
+
+Example:
+  worklogger hooks install
+  worklogger hooks status
+  worklogger hooks uninstall`,
+}
+
+// hooksInstallCmd represents the hooks install command
+var hooksInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install or update the post-commit hook",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		installHook(cmd)
+	},
+}
+
+// hooksUninstallCmd represents the hooks uninstall command
+var hooksUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove the post-commit hook, restoring the one it replaced",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := data.GitHooksDir(".")
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		path := filepath.Join(dir, hookName)
+		script, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println("No post-commit hook is installed.")
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to read hook: %v\n", err)
+			return
+		}
+
+		if !isOurHook(string(script)) && !isLegacyHook(string(script)) {
+			fmt.Printf("⚠️  %s wasn't installed by worklogger; leaving it alone.\n", path)
+			return
+		}
+
+		if err := os.Remove(path); err != nil {
+			cmd.PrintErrf("failed to remove hook: %v\n", err)
+			return
+		}
+
+		chained := path + chainedHookSuffix
+		if _, err := os.Stat(chained); err == nil {
+			if err := os.Rename(chained, path); err != nil {
+				cmd.PrintErrf("failed to restore the previous hook: %v\n", err)
+				return
+			}
+			fmt.Printf("🪝 Removed the hook and restored the previous one in %s\n", path)
+			return
+		}
+
+		fmt.Printf("🪝 Removed the hook from %s\n", path)
+	},
+}
+
+// hooksStatusCmd represents the hooks status command
+var hooksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check that the post-commit hook is installed and working",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := data.GitHooksDir(".")
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		path := filepath.Join(dir, hookName)
+		fmt.Printf("Hook: %s\n", path)
+
+		problems, err := checkHook(path)
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Println("❌ Not installed. Run `worklogger hooks install`.")
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to check hook: %v\n", err)
+			return
+		}
+
+		if chained := path + chainedHookSuffix; fileExists(chained) {
+			fmt.Printf("Runs first: %s\n", chained)
+		}
+
+		if len(problems) == 0 {
+			fmt.Println("✅ Installed and up to date.")
+			return
+		}
+
+		for _, p := range problems {
+			fmt.Printf("⚠️  %s\n", p)
+		}
+	},
+}
+
+// setupHookCmd is the old name of 'hooks install'.
+var setupHookCmd = &cobra.Command{
+	Use:        "setup-hook",
+	Aliases:    []string{"setupHook"},
+	Short:      "Install Git post-commit hook for auto-logging",
+	Hidden:     true,
+	Deprecated: "use 'worklogger hooks install' instead.",
+	Args:       cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		installHook(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(hooksCmd, setupHookCmd)
+	hooksCmd.AddCommand(hooksInstallCmd, hooksUninstallCmd, hooksStatusCmd)
+
+	hooksInstallCmd.Flags().BoolVar(&hooksForceFlag, "force", false, "Replace a hook kept from before instead of refusing")
+}
+
+func installHook(cmd *cobra.Command) {
+	dir, err := data.GitHooksDir(".")
+	if err != nil {
+		fmt.Printf("⚠️  %s\n", err.Error())
+		return
+	}
+
+	exe, err := hookBinary()
+	if err != nil {
+		cmd.PrintErrf("failed to find the worklogger binary: %v\n", err)
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		cmd.PrintErrf("failed to create hooks directory: %v\n", err)
+		return
+	}
+
+	path := filepath.Join(dir, hookName)
+	chained := path + chainedHookSuffix
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		cmd.PrintErrf("failed to read existing hook: %v\n", err)
+		return
+	case isOurHook(string(existing)) || isLegacyHook(string(existing)):
+		// Updated in place; a chained hook stays chained.
+	default:
+		if fileExists(chained) && !hooksForceFlag {
+			fmt.Printf("⚠️  Both %s and %s exist. Move one of them, or pass --force to replace the kept one.\n", path, chained)
+			return
+		}
+		if err := os.Rename(path, chained); err != nil {
+			cmd.PrintErrf("failed to keep the existing hook: %v\n", err)
+			return
+		}
+		fmt.Printf("Kept the existing hook as %s; it still runs first.\n", chained)
+	}
+
+	if err := os.WriteFile(path, []byte(hookScript(exe)), 0755); err != nil {
+		cmd.PrintErrf("failed to write hook: %v\n", err)
+		return
+	}
+	// WriteFile keeps the mode of a file that was already there.
+	if err := os.Chmod(path, 0755); err != nil {
+		cmd.PrintErrf("failed to make hook executable: %v\n", err)
+		return
+	}
+
+	fmt.Printf("🪝 Installed the post-commit hook in %s\n", path)
+	fmt.Println("Commits are now recorded on the active session.")
+}
+
+// hookScript is the post-commit hook that records a commit with exe. The
+// commit details are passed as --flag=value so values starting with a dash,
+// quotes and multi-line messages arrive intact.
+func hookScript(exe string) string {
+	return "#!/bin/sh\n" + hookMarker + `. Managed by 'worklogger hooks';
+# edits are lost on the next 'worklogger hooks install'.
+
+status=0
+previous="$0` + chainedHookSuffix + `"
+if [ -x "$previous" ]; then
+	"$previous" "$@" || status=$?
+fi
+
+worklogger=` + shellQuote(exe) + `
+if [ ! -x "$worklogger" ]; then
+	echo "worklogger: $worklogger is missing; run 'worklogger hooks install' again" >&2
+	exit $status
+fi
+
+# Git runs the hook from the top of the work tree. Only repositories set up
+# with 'worklogger init' record commits.
+[ -d .worklogger ] || exit $status
+
+"$worklogger" read-commit \
+	--hash="$(git rev-parse HEAD)" \
+	--message="$(git log -1 --pretty=%B)" \
+	--author="$(git log -1 --pretty=%an)" \
+	--date="$(git log -1 --pretty=%ad)" >/dev/null || true
+
+exit $status
+`
+}
+
+// checkHook lists what is wrong with the hook at path. It returns
+// os.ErrNotExist when no worklogger hook is installed there.
+func checkHook(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	script := string(content)
+
+	if isLegacyHook(script) {
+		return []string{"Broken: this hook was written by 'setup-hook' and calls 'record-commit', which doesn't exist. Run `worklogger hooks install` to replace it."}, nil
+	}
+	if !isOurHook(script) {
+		return nil, os.ErrNotExist
+	}
+
+	var problems []string
+
+	if info.Mode()&0111 == 0 {
+		problems = append(problems, "Broken: the hook isn't executable, so git skips it. Run `worklogger hooks install` to fix it.")
+	}
+
+	if exe := hookExecutable(script); exe == "" {
+		problems = append(problems, "Broken: the hook doesn't name a worklogger binary. Run `worklogger hooks install` to rewrite it.")
+	} else if info, err := os.Stat(exe); err != nil || info.Mode()&0111 == 0 {
+		problems = append(problems, fmt.Sprintf("Broken: the hook runs %s, which is missing. Run `worklogger hooks install` to point it at this binary.", exe))
+	}
+
+	if chained := path + chainedHookSuffix; fileExists(chained) {
+		if info, err := os.Stat(chained); err == nil && info.Mode()&0111 == 0 {
+			problems = append(problems, fmt.Sprintf("The kept hook %s isn't executable, so it doesn't run.", chained))
+		}
+	}
+
+	if len(problems) == 0 {
+		if exe, err := hookBinary(); err == nil && script != hookScript(exe) {
+			problems = append(problems, "Stale: the hook was written by another worklogger version or binary. Run `worklogger hooks install` to update it.")
+		}
+	}
+
+	return problems, nil
+}
+
+func isOurHook(script string) bool {
+	return strings.Contains(script, hookMarker)
+}
+
+// isLegacyHook spots the script 'setup-hook' used to write, which called a
+// command that doesn't exist.
+func isLegacyHook(script string) bool {
+	return strings.Contains(script, "worklogger record-commit")
+}
+
+// hookExecutable reads the binary a hook runs back from its script.
+func hookExecutable(script string) string {
+	for _, line := range strings.Split(script, "\n") {
+		if value, ok := strings.CutPrefix(line, "worklogger="); ok {
+			return shellUnquote(value)
+		}
+	}
+	return ""
+}
+
+// hookBinary is the worklogger binary hooks run: this one.
+func hookBinary() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shellUnquote(s string) string {
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "'"), "'")
+	return strings.ReplaceAll(s, `'\''`, "'")
+}
@@ -72,7 +72,7 @@ var initCmd = &cobra.Command{
 		fmt.Println("Migrations ran successfully!")
 		fmt.Println()
 		fmt.Println("Next steps:")
-		fmt.Println("  • Run `worklogger setup-hook` to enable automatic commit tracking")
+		fmt.Println("  • Run `worklogger hooks install` to enable automatic commit tracking")
 		fmt.Println("  • OR run `worklogger sync` to import existing commit history manually")
 	},
 }
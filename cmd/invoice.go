@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+	"github.com/tormgibbs/worklogger/invoice"
+)
+
+var (
+	invoiceClientFlag  string
+	invoiceFromFlag    string
+	invoiceToFlag      string
+	invoiceFormatFlag  string
+	invoiceOutFlag     string
+	invoicePreviewFlag bool
+)
+
+// invoiceCmd represents the invoice command
+var invoiceCmd = &cobra.Command{
+	Use:   "invoice",
+	Short: "Invoice a client for billable time",
+	Long: `Bill a client for the active time of their projects' sessions started
+between --from and --to, both days included. Paused time is not billed,
+and sessions that are still running, marked --non-billable or already on an
+invoice are left out.
+
+The invoice gets the next number (invoice.prefix, the year and a sequence)
+and its sessions are recorded so they can't be invoiced twice. Use --preview
+to see it without recording anything.
+
+It is written as Markdown, HTML or CSV. Put invoice.md.tmpl,
+invoice.html.tmpl or invoice.csv.tmpl in .worklogger/templates (or the
+invoice.templates directory) to use your own layout.
+
+Example:
+  worklogger invoice --client Acme --from 2025-03-01 --to 2025-03-31 --preview
+  worklogger invoice --client Acme --from 2025-03-01 --to 2025-03-31 --format html --out acme-march.html
+  worklogger invoice show INV-2025-0003 --format csv
+  worklogger invoice list`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := strings.TrimSpace(invoiceClientFlag)
+		if client == "" {
+			fmt.Println("⚠️  Give the client to invoice with --client.")
+			return
+		}
+
+		from, to, err := invoicePeriod(invoiceFromFlag, invoiceToFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		inv, err := models.Invoices.Draft(client, from, to, viper.GetString("invoice.currency"))
+		if errors.Is(err, data.ErrNoRate) {
+			fmt.Printf("⚠️  %s. Set one with `worklogger rate set <amount>`.\n", err.Error())
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to prepare invoice: %v\n", err)
+			return
+		}
+
+		if inv.Running > 0 {
+			fmt.Fprintf(os.Stderr, "⚠️  Left out %d running session(s); stop them to bill their time.\n", inv.Running)
+		}
+
+		if len(inv.Lines) == 0 {
+			fmt.Printf("Nothing to invoice for %s between %s and %s.\n", client, invoiceFromFlag, invoiceToFlag)
+			return
+		}
+
+		dir := viper.GetString("invoice.templates")
+
+		if !invoicePreviewFlag {
+			// Catch template mistakes before a number is used up.
+			if err := invoice.Render(io.Discard, invoiceFormatFlag, inv, dir); err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
+
+			err := models.Invoices.Record(inv, viper.GetString("invoice.prefix"))
+			if errors.Is(err, data.ErrAlreadyInvoiced) {
+				fmt.Printf("⚠️  %s. Run the command again to invoice what is left.\n", err.Error())
+				return
+			}
+			if err != nil {
+				cmd.PrintErrf("failed to record invoice: %v\n", err)
+				return
+			}
+		}
+
+		if err := writeInvoice(inv, dir); err != nil {
+			cmd.PrintErrf("failed to write invoice: %v\n", err)
+		}
+	},
+}
+
+// invoiceShowCmd represents the invoice show command
+var invoiceShowCmd = &cobra.Command{
+	Use:   "show <number>",
+	Short: "Write a recorded invoice again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		inv, err := models.Invoices.GetByNumber(args[0])
+		if errors.Is(err, data.ErrRecordNotFound) {
+			fmt.Printf("⚠️  No invoice numbered %s.\n", args[0])
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to get invoice: %v\n", err)
+			return
+		}
+
+		if err := writeInvoice(inv, viper.GetString("invoice.templates")); err != nil {
+			cmd.PrintErrf("failed to write invoice: %v\n", err)
+		}
+	},
+}
+
+// invoiceListCmd represents the invoice list command
+var invoiceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded invoices",
+	Run: func(cmd *cobra.Command, args []string) {
+		invoices, err := models.Invoices.GetAll()
+		if err != nil {
+			cmd.PrintErrf("failed to get invoices: %v\n", err)
+			return
+		}
+
+		if len(invoices) == 0 {
+			fmt.Println("No invoices yet.")
+			return
+		}
+
+		fmt.Printf("%-16s  %-20s  %-23s  %8s  %12s\n", "NUMBER", "CLIENT", "PERIOD", "HOURS", "TOTAL")
+		for _, inv := range invoices {
+			period := fmt.Sprintf("%s – %s",
+				inv.From.In(data.Location()).Format("2006-01-02"),
+				inv.To.AddDate(0, 0, -1).In(data.Location()).Format("2006-01-02"))
+			fmt.Printf("%-16s  %-20s  %-23s  %8.2f  %8.2f %s\n",
+				inv.Number, truncate(inv.Client, 20), period, inv.Hours, inv.Total, inv.Currency)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(invoiceCmd)
+	invoiceCmd.AddCommand(invoiceShowCmd, invoiceListCmd)
+
+	invoiceCmd.Flags().StringVar(&invoiceClientFlag, "client", "", "Client to invoice")
+	invoiceCmd.Flags().StringVar(&invoiceFromFlag, "from", "", "First day to bill, e.g. 2025-03-01")
+	invoiceCmd.Flags().StringVar(&invoiceToFlag, "to", "", "Last day to bill, e.g. 2025-03-31")
+	invoiceCmd.Flags().BoolVar(&invoicePreviewFlag, "preview", false, "Show the invoice without numbering or recording it")
+
+	for _, c := range []*cobra.Command{invoiceCmd, invoiceShowCmd} {
+		c.Flags().StringVar(&invoiceFormatFlag, "format", "md", "Output format: "+strings.Join(invoice.Formats, ", "))
+		c.Flags().StringVarP(&invoiceOutFlag, "out", "o", "", "File to write the invoice to (default: standard output)")
+	}
+
+	viper.SetDefault("invoice.currency", "USD")
+	viper.SetDefault("invoice.prefix", "INV-")
+	viper.SetDefault("invoice.templates", ".worklogger/templates")
+}
+
+// invoicePeriod parses --from and --to as days in the report timezone and
+// returns the period with an exclusive end.
+func invoicePeriod(fromValue, toValue string) (time.Time, time.Time, error) {
+	if fromValue == "" || toValue == "" {
+		return time.Time{}, time.Time{}, errors.New("give the period to invoice with --from and --to")
+	}
+
+	from, err := time.ParseInLocation("2006-01-02", fromValue, data.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q (use 2006-01-02)", fromValue)
+	}
+
+	to, err := time.ParseInLocation("2006-01-02", toValue, data.Location())
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q (use 2006-01-02)", toValue)
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errors.New("--to is before --from")
+	}
+
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+// writeInvoice renders inv to --out, or to standard output.
+func writeInvoice(inv *data.Invoice, dir string) error {
+	if invoiceOutFlag == "" {
+		return invoice.Render(os.Stdout, invoiceFormatFlag, inv, dir)
+	}
+
+	f, err := os.Create(invoiceOutFlag)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := invoice.Render(f, invoiceFormatFlag, inv, dir); err != nil {
+		return err
+	}
+
+	number := inv.Number
+	if number == "" {
+		number = "preview"
+	}
+	fmt.Printf("🧾 Invoice %s for %s: %.2fh, %.2f %s → %s\n", number, inv.Client, inv.Hours, inv.Total, inv.Currency, invoiceOutFlag)
+
+	return f.Close()
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	kpiHoursFlag    float64
+	kpiSessionsFlag int
+	kpiCommitsFlag  int
+	kpiClearFlag    bool
+	kpiWeeksFlag    int
+)
+
+// kpiTargetCmd represents the kpis target command
+var kpiTargetCmd = &cobra.Command{
+	Use:   "target <kpi>",
+	Short: "Set a weekly target for a KPI",
+	Long: `Give a KPI a weekly target: hours logged, sessions worked or commits
+made on sessions with the KPI. Once an org has KPIs with targets, org-mode
+sessions only accept those KPIs.
+
+Example:
+  worklogger kpi target delivery --hours 10
+  worklogger kpi target reviews --sessions 5
+  worklogger kpi target shipping --commits 20
+  worklogger kpi target delivery --clear`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimSpace(args[0])
+		if name == "" {
+			fmt.Println("⚠️  The KPI needs a name.")
+			return
+		}
+
+		flags := cmd.Flags()
+		set := 0
+		for _, f := range []string{"hours", "sessions", "commits", "clear"} {
+			if flags.Changed(f) {
+				set++
+			}
+		}
+		if set != 1 {
+			fmt.Println("⚠️  Give exactly one of --hours, --sessions, --commits or --clear.")
+			return
+		}
+
+		if kpiClearFlag {
+			err := models.Labels.ClearTarget(name)
+			if errors.Is(err, data.ErrRecordNotFound) {
+				fmt.Printf("⚠️  No KPI called %s.%s\n", name, suggestion(data.LabelKPI, name))
+				return
+			}
+			if err != nil {
+				cmd.PrintErrf("failed to clear target: %v\n", err)
+				return
+			}
+			fmt.Printf("🎯 Cleared the target of %s\n", name)
+			return
+		}
+
+		metric, target := data.MetricHours, kpiHoursFlag
+		switch {
+		case flags.Changed("sessions"):
+			metric, target = data.MetricSessions, float64(kpiSessionsFlag)
+		case flags.Changed("commits"):
+			metric, target = data.MetricCommits, float64(kpiCommitsFlag)
+		}
+
+		if target <= 0 {
+			fmt.Println("⚠️  The target must be more than zero.")
+			return
+		}
+
+		if err := models.Labels.SetTarget(name, metric, target); err != nil {
+			cmd.PrintErrf("failed to set target: %v\n", err)
+			return
+		}
+
+		fmt.Printf("🎯 %s: %s per week\n", name, formatKPIValue(metric, target))
+	},
+}
+
+// kpiReportCmd represents the kpis report command
+var kpiReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show weekly progress against KPI targets",
+	Run: func(cmd *cobra.Command, args []string) {
+		if kpiWeeksFlag < 1 {
+			fmt.Println("⚠️  --weeks must be at least 1.")
+			return
+		}
+
+		report, err := data.GetKPIReport(db, kpiWeeksFlag)
+		if err != nil {
+			cmd.PrintErrf("failed to get KPI report: %v\n", err)
+			return
+		}
+
+		if len(report) == 0 {
+			fmt.Println("No KPI targets yet. Set one with `worklogger kpi target <kpi> --hours 10`.")
+			return
+		}
+
+		fmt.Println("📊 KPI Progress (weeks start on Monday)")
+		for _, kpi := range report {
+			fmt.Printf("\n🎯 %s — %s per week\n", kpi.Name, formatKPIValue(kpi.Metric, kpi.Target))
+			for _, w := range kpi.Weeks {
+				week, _ := time.ParseInLocation("2006-01-02", w.Week, data.Location())
+
+				mark := ""
+				if w.Met {
+					mark = " ✅"
+				}
+
+				fmt.Printf("   %s  %-12s %4.0f%%  %s%s\n",
+					week.Format("Jan 02"), formatKPIValue(kpi.Metric, w.Actual), w.Percent, progressBar(w.Percent), mark)
+			}
+		}
+	},
+}
+
+func init() {
+	kpisCmd.Aliases = append(kpisCmd.Aliases, "kpi")
+	kpisCmd.AddCommand(kpiTargetCmd, kpiReportCmd)
+
+	kpiTargetCmd.Flags().Float64Var(&kpiHoursFlag, "hours", 0, "Hours to log on the KPI each week")
+	kpiTargetCmd.Flags().IntVar(&kpiSessionsFlag, "sessions", 0, "Sessions to work on the KPI each week")
+	kpiTargetCmd.Flags().IntVar(&kpiCommitsFlag, "commits", 0, "Commits to make on sessions with the KPI each week")
+	kpiTargetCmd.Flags().BoolVar(&kpiClearFlag, "clear", false, "Remove the target, keeping the KPI")
+
+	kpiReportCmd.Flags().IntVar(&kpiWeeksFlag, "weeks", 4, "Number of weeks to show, counting this one")
+}
+
+// checkOrgKPIs makes sure an org-mode session has every KPI listed in
+// org.required_kpis and, once the org has KPIs with targets, only uses
+// those.
+func checkOrgKPIs(mode string, kpis []string) error {
+	if mode != "org" {
+		return nil
+	}
+
+	if err := checkRequiredKPIs(kpis); err != nil {
+		return err
+	}
+
+	targets, err := models.Labels.Targets()
+	if err != nil {
+		return fmt.Errorf("failed to get KPI targets: %w", err)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	defined := make([]string, len(targets))
+	known := make(map[string]bool, len(targets))
+	for i, t := range targets {
+		defined[i] = t.Name
+		known[t.Name] = true
+	}
+
+	for _, kpi := range kpis {
+		if !known[kpi] {
+			return fmt.Errorf("%q is not one of the org's KPIs (%s).%s",
+				kpi, strings.Join(defined, ", "), didYouMean(data.Suggest(kpi, defined)))
+		}
+	}
+
+	return nil
+}
+
+func formatKPIValue(metric string, value float64) string {
+	switch metric {
+	case data.MetricHours:
+		return fmt.Sprintf("%.1fh", value)
+	case data.MetricSessions:
+		if value == 1 {
+			return "1 session"
+		}
+		return fmt.Sprintf("%.0f sessions", value)
+	default:
+		if value == 1 {
+			return "1 commit"
+		}
+		return fmt.Sprintf("%.0f commits", value)
+	}
+}
+
+// progressBar draws percent as a ten-block bar, full at 100 or more.
+func progressBar(percent float64) string {
+	filled := int(percent / 10)
+	if filled > 10 {
+		filled = 10
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return strings.Repeat("█", filled) + strings.Repeat("░", 10-filled)
+}
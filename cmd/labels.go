@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = newLabelCmd(data.LabelTag, "tags", "tag", "Tag")
+
+// kpisCmd represents the kpis command
+var kpisCmd = newLabelCmd(data.LabelKPI, "kpis", "KPI", "KPI")
+
+func init() {
+	rootCmd.AddCommand(tagsCmd, kpisCmd)
+}
+
+// newLabelCmd builds the catalog commands for tags or KPIs, which work the
+// same way.
+func newLabelCmd(kind, use, noun, title string) *cobra.Command {
+	parent := &cobra.Command{
+		Use:   use,
+		Short: fmt.Sprintf("Manage the %s catalog", noun),
+		Long: fmt.Sprintf(`Every %[1]s used on a session is kept in a catalog. List it with usage
+counts and total time, and fix typos by renaming or merging: existing
+sessions are rewritten in one transaction.
+
+Set catalog.strict: true in ~/.worklogger.yaml to only accept %[1]ss that
+are already in the catalog; add new ones with 'worklogger %[2]s add'.
+
+Example:
+  worklogger %[2]s list
+  worklogger %[2]s rename bakcend backend
+  worklogger %[2]s merge frontend-ui ui frontend`, noun, use),
+	}
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: fmt.Sprintf("List %ss with usage counts and total time", noun),
+		Run: func(cmd *cobra.Command, args []string) {
+			stats, err := models.Labels.Stats(kind)
+			if err != nil {
+				cmd.PrintErrf("failed to get %ss: %v\n", noun, err)
+				return
+			}
+
+			if len(stats) == 0 {
+				fmt.Printf("No %ss yet. Use --%s when you start a session.\n", noun, kind)
+				return
+			}
+
+			fmt.Printf("%-30s  %8s  %8s\n", strings.ToUpper(noun), "SESSIONS", "HOURS")
+			for _, s := range stats {
+				fmt.Printf("%-30s  %8d  %8.2f\n", truncate(s.Name, 30), s.Sessions, s.Hours)
+			}
+		},
+	}
+
+	add := &cobra.Command{
+		Use:   "add <name>",
+		Short: fmt.Sprintf("Add a %s to the catalog", noun),
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := strings.TrimSpace(args[0])
+			if name == "" {
+				fmt.Printf("⚠️  The %s needs a name.\n", noun)
+				return
+			}
+
+			err := models.Labels.Add(kind, name)
+			if errors.Is(err, data.ErrLabelExists) {
+				fmt.Printf("%s %s is already in the catalog.\n", title, name)
+				return
+			}
+			if err != nil {
+				cmd.PrintErrf("failed to add %s: %v\n", noun, err)
+				return
+			}
+
+			fmt.Printf("🏷  Added %s %s\n", noun, name)
+		},
+	}
+
+	rename := &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: fmt.Sprintf("Rename a %s on every session", noun),
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			from, to := strings.TrimSpace(args[0]), strings.TrimSpace(args[1])
+			if to == "" || from == to {
+				fmt.Printf("⚠️  Give a new name for %s.\n", from)
+				return
+			}
+
+			n, err := models.Labels.Rename(kind, from, to)
+			switch {
+			case errors.Is(err, data.ErrLabelExists):
+				fmt.Printf("⚠️  %s %s already exists. Use `worklogger %s merge %s %s` to fold one into the other.\n", title, to, use, from, to)
+				return
+			case errors.Is(err, data.ErrRecordNotFound):
+				fmt.Printf("⚠️  No %s called %s.%s\n", noun, from, suggestion(kind, from))
+				return
+			case err != nil:
+				cmd.PrintErrf("failed to rename %s: %v\n", noun, err)
+				return
+			}
+
+			fmt.Printf("🏷  Renamed %s %s to %s on %d session(s)\n", noun, from, to, n)
+		},
+	}
+
+	merge := &cobra.Command{
+		Use:   "merge <from>... <into>",
+		Short: fmt.Sprintf("Fold %ss into another one on every session", noun),
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			into := strings.TrimSpace(args[len(args)-1])
+			from := nonEmpty(args[:len(args)-1])
+			for _, name := range from {
+				if name == into {
+					fmt.Printf("⚠️  Can't merge %s into itself.\n", into)
+					return
+				}
+			}
+
+			n, err := models.Labels.Merge(kind, from, into)
+			if errors.Is(err, data.ErrRecordNotFound) {
+				missing := strings.TrimPrefix(err.Error(), data.ErrRecordNotFound.Error()+": ")
+				fmt.Printf("⚠️  No %s called %s.%s\n", noun, missing, suggestion(kind, missing))
+				return
+			}
+			if err != nil {
+				cmd.PrintErrf("failed to merge %ss: %v\n", noun, err)
+				return
+			}
+
+			fmt.Printf("🏷  Merged %s into %s on %d session(s)\n", strings.Join(from, ", "), into, n)
+		},
+	}
+
+	remove := &cobra.Command{
+		Use:   "delete <name>",
+		Short: fmt.Sprintf("Remove a %s from the catalog and every session", noun),
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			name := strings.TrimSpace(args[0])
+
+			n, err := models.Labels.Delete(kind, name)
+			if errors.Is(err, data.ErrRecordNotFound) {
+				fmt.Printf("⚠️  No %s called %s.%s\n", noun, name, suggestion(kind, name))
+				return
+			}
+			if err != nil {
+				cmd.PrintErrf("failed to delete %s: %v\n", noun, err)
+				return
+			}
+
+			fmt.Printf("🗑  Deleted %s %s from %d session(s)\n", noun, name, n)
+		},
+	}
+
+	parent.AddCommand(list, add, rename, merge, remove)
+	return parent
+}
+
+// checkCatalog refuses tags and KPIs missing from the catalog when
+// catalog.strict is set, suggesting close matches.
+func checkCatalog(tags, kpis []string) error {
+	if !viper.GetBool("catalog.strict") {
+		return nil
+	}
+
+	for _, check := range []struct {
+		kind, noun, use string
+		names           []string
+	}{
+		{data.LabelTag, "tag", "tags", tags},
+		{data.LabelKPI, "KPI", "kpis", kpis},
+	} {
+		if len(check.names) == 0 {
+			continue
+		}
+
+		catalog, err := models.Labels.Names(check.kind)
+		if err != nil {
+			return fmt.Errorf("failed to read the %s catalog: %w", check.noun, err)
+		}
+
+		known := make(map[string]bool, len(catalog))
+		for _, name := range catalog {
+			known[name] = true
+		}
+
+		for _, name := range check.names {
+			if !known[name] {
+				return fmt.Errorf("unknown %s %q.%s Add it with `worklogger %s add %s`",
+					check.noun, name, didYouMean(data.Suggest(name, catalog)), check.use, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// suggestion is a " Did you mean ...?" hint for a name missing from the
+// catalog, or empty when nothing is close.
+func suggestion(kind, name string) string {
+	catalog, err := models.Labels.Names(kind)
+	if err != nil {
+		return ""
+	}
+	return didYouMean(data.Suggest(name, catalog))
+}
+
+func didYouMean(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	if len(names) > 3 {
+		names = names[:3]
+	}
+	return fmt.Sprintf(" Did you mean %s?", strings.Join(names, " or "))
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <session-id> <session-id>...",
+	Short: "Fold several sessions of the same task into one",
+	Long: `Combine sessions that belong to the same task.
+
+The earliest session is kept and stretched to cover all of them; the time
+between them counts as paused. Intervals, commits and pomodoros move over,
+tags and KPIs are combined and notes are joined. The other sessions are
+removed. The sessions' intervals may not overlap.
+
+Example:
+  worklogger merge 12 15 16`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		seen := make(map[int]bool, len(args))
+		var sessions []*data.TaskSession
+
+		for _, arg := range args {
+			id, err := strconv.Atoi(arg)
+			if err != nil {
+				fmt.Printf("⚠️  Invalid session ID: %s\n", arg)
+				return
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			ts, err := models.TaskSessions.GetByID(id)
+			if err != nil {
+				if errors.Is(err, data.ErrRecordNotFound) {
+					fmt.Printf("Session #%d not found.\n", id)
+					return
+				}
+				cmd.PrintErr(fmt.Errorf("failed to load session: %w", err))
+				fmt.Println()
+				return
+			}
+			sessions = append(sessions, ts)
+		}
+
+		if len(sessions) < 2 {
+			fmt.Println("⚠️  Give at least two different sessions to merge.")
+			return
+		}
+
+		var tags, kpis []string
+		for _, ts := range sessions {
+			sessionTags, err := models.SessionTags.GetBySession(ts.ID)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to load tags: %w", err))
+				fmt.Println()
+				return
+			}
+			tags = mergeUnique(tags, sessionTags)
+
+			sessionKPIs, err := models.SessionKPI.GetBySession(ts.ID)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to load KPIs: %w", err))
+				fmt.Println()
+				return
+			}
+			kpis = mergeUnique(kpis, sessionKPIs)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		merged, err := models.MergeSessions(tx, sessions)
+		if err != nil {
+			if errors.Is(err, data.ErrOverlappingInterval) {
+				fmt.Println("⚠️  These sessions overlap each other and can't be merged.")
+				return
+			}
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if err := models.SessionTags.Update(tx, merged.ID, tags); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to update tags: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if err := models.SessionKPI.Update(tx, merged.ID, kpis); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to update KPIs: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		var ids []string
+		for _, ts := range sessions[1:] {
+			ids = append(ids, fmt.Sprintf("#%d", ts.ID))
+		}
+
+		fmt.Printf("🔗 Merged %s into session #%d\n", strings.Join(ids, ", "), merged.ID)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var noteSessionFlag int
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note <text>",
+	Short: "Add a timestamped note to the current session",
+	Long: `Keep a journal while you work. Each note is stamped with the time it was
+taken and shows up in 'worklogger log' between the session's commits.
+
+Notes go to the active session, or to --session <id> for an earlier one.
+Use --at to date a note back, e.g. --at "20m ago".
+
+Example:
+  worklogger note "Found the cause: the cache key ignores the locale"
+  worklogger note --session 42 --at 15:10 "Paired with Sam on the migration"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		body := strings.TrimSpace(strings.Join(args, " "))
+		if body == "" {
+			fmt.Println("⚠️  The note is empty.")
+			return
+		}
+
+		at, err := resolveAt(atFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		var ts *data.TaskSession
+		if noteSessionFlag != 0 {
+			ts, err = models.TaskSessions.GetByID(noteSessionFlag)
+			if errors.Is(err, data.ErrRecordNotFound) {
+				fmt.Printf("⚠️  Session #%d not found.\n", noteSessionFlag)
+				return
+			}
+		} else {
+			ts, err = models.TaskSessions.Get(workspace)
+		}
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to get session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if ts == nil {
+			fmt.Println("You don't have an active session. Pass --session <id> to add a note to an earlier one.")
+			return
+		}
+
+		// An active session from Get only carries its ID.
+		if ts.StartedAt.IsZero() {
+			if ts, err = models.TaskSessions.GetByID(ts.ID); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to get session: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if at.Before(ts.StartedAt) {
+			fmt.Printf("⚠️  Session #%d started at %s. Pick a later --at.\n",
+				ts.ID, ts.StartedAt.In(data.Location()).Format("2006-01-02 15:04"))
+			return
+		}
+
+		if ts.EndedAt != nil && at.After(*ts.EndedAt) {
+			if atFlag != "" {
+				fmt.Printf("⚠️  Session #%d ended at %s. Pick an earlier --at.\n",
+					ts.ID, ts.EndedAt.In(data.Location()).Format("2006-01-02 15:04"))
+				return
+			}
+			// A note on a finished session belongs to its end.
+			at = *ts.EndedAt
+		}
+
+		note := &data.SessionNote{SessionID: ts.ID, Body: body, NotedAt: at}
+		if err := models.SessionNotes.Create(note); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to save note: %w", err))
+			fmt.Println()
+			return
+		}
+
+		fmt.Printf("📝 Noted on session #%d at %s\n", ts.ID, at.In(data.Location()).Format("15:04"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+
+	noteCmd.Flags().IntVar(&noteSessionFlag, "session", 0, "ID of the session to add the note to (default: the active session)")
+	noteCmd.Flags().StringVar(&atFlag, "at", "", `When the note was taken, e.g. "14:30" or "20m ago" (default now)`)
+}
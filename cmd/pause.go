@@ -1,19 +1,34 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
 )
 
+var reasonFlag string
+
 // pauseCmd represents the pause command
 var pauseCmd = &cobra.Command{
 	Use:   "pause",
 	Short: "Pause the current active session",
 	Long: `Pause an ongoing task session. 
-This ends the current interval but keeps the session open so you can resume it later.`,
+This ends the current interval but keeps the session open so you can resume it later.
+
+Use --at if you actually stopped working earlier, e.g. --at "20m ago".
+Use --reason to say why: meeting, break, interrupt or any text of your own.
+See where paused time goes with 'worklogger pauses'.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ts, err := models.TaskSessions.Get()
+		at, err := resolveAt(atFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		ts, err := models.TaskSessions.Get(workspace)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to check active session: %w", err))
 			fmt.Println()
@@ -25,7 +40,11 @@ This ends the current interval but keeps the session open so you can resume it l
 			return
 		}
 
-		tsi, err := models.TaskSessionIntervals.End(ts)
+		tsi, err := models.TaskSessionIntervals.End(ts, at)
+		if errors.Is(err, data.ErrBeforeActivity) {
+			fmt.Println("⚠️  The session was resumed after that time. Pick a later --at.")
+			return
+		}
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to pause session: %w", err))
 			fmt.Println()
@@ -37,11 +56,31 @@ This ends the current interval but keeps the session open so you can resume it l
 			return
 		}
 
-		formattedTime := tsi.EndTime.Format("2006-01-02 15:04:05")
-		fmt.Printf("Session paused at %v\n", formattedTime)
+		reason := strings.TrimSpace(reasonFlag)
+		if reason != "" {
+			if err := models.TaskSessionIntervals.SetPauseReason(tsi.ID, reason); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to save pause reason: %w", err))
+				fmt.Println()
+			}
+		}
+
+		if err := models.Pomodoros.AbandonRunning(ts.ID, at); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to update pomodoros: %w", err))
+			fmt.Println()
+		}
+
+		formattedTime := tsi.EndTime.In(data.Location()).Format("2006-01-02 15:04:05")
+		if reason != "" {
+			fmt.Printf("Session paused at %v (%s)\n", formattedTime, reason)
+		} else {
+			fmt.Printf("Session paused at %v\n", formattedTime)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(pauseCmd)
+
+	pauseCmd.Flags().StringVar(&atFlag, "at", "", `When the pause began, e.g. "14:30" or "20m ago" (default now)`)
+	pauseCmd.Flags().StringVarP(&reasonFlag, "reason", "r", "", "Why you are pausing: meeting, break, interrupt or free text")
 }
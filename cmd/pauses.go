@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var pauseDaysFlag int
+
+// pausesCmd represents the pauses command
+var pausesCmd = &cobra.Command{
+	Use:   "pauses",
+	Short: "Break paused time down by reason",
+	Long: `Show where paused time went, per day and per task, grouped by the
+reason given to 'worklogger pause --reason'. Pauses without a reason are
+listed as "unspecified"; automatic pauses from 'worklogger watch' as "idle".
+
+Example:
+  worklogger pauses --days 14`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if pauseDaysFlag <= 0 {
+			fmt.Println("⚠️  --days must be at least 1.")
+			return
+		}
+
+		stats, err := data.GetPauseBreakdown(db, pauseDaysFlag)
+		if err != nil {
+			cmd.PrintErrf("failed to get pause breakdown: %v\n", err)
+			return
+		}
+
+		if len(stats) == 0 {
+			fmt.Printf("No paused time in the last %d days.\n", pauseDaysFlag)
+			return
+		}
+
+		fmt.Printf("⏸  Paused time by reason (last %d days)\n", pauseDaysFlag)
+
+		fmt.Println()
+		fmt.Println("By day:")
+		printPauseGroups(stats, func(s *data.PauseStat) string { return s.Date })
+
+		fmt.Println()
+		fmt.Println("By task:")
+		printPauseGroups(stats, func(s *data.PauseStat) string { return s.Task })
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pausesCmd)
+
+	pausesCmd.Flags().IntVar(&pauseDaysFlag, "days", 7, "Number of days to include, counting today")
+}
+
+// printPauseGroups totals the stats per group and reason, keeping groups and
+// reasons in the order they first appear.
+func printPauseGroups(stats []*data.PauseStat, groupOf func(*data.PauseStat) string) {
+	var groups []string
+	reasons := make(map[string][]string)
+	totals := make(map[string]map[string]time.Duration)
+
+	for _, s := range stats {
+		group := groupOf(s)
+		if totals[group] == nil {
+			groups = append(groups, group)
+			totals[group] = make(map[string]time.Duration)
+		}
+		if _, seen := totals[group][s.Reason]; !seen {
+			reasons[group] = append(reasons[group], s.Reason)
+		}
+		totals[group][s.Reason] += s.Paused
+	}
+
+	for _, group := range groups {
+		var sum time.Duration
+		for _, d := range totals[group] {
+			sum += d
+		}
+
+		fmt.Printf("• %s: %s\n", group, formatDuration(sum))
+		for _, reason := range reasons[group] {
+			fmt.Printf("    %-14s %s\n", reason, formatDuration(totals[group][reason]))
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tormgibbs/worklogger/data"
+)
+
+// parsePomodoro parses a "focus/break" spec such as "25/5" (minutes) or
+// "50m/10m".
+func parsePomodoro(value string) (time.Duration, time.Duration, error) {
+	focusPart, breakPart, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid --pomodoro %q (use focus/break, e.g. 25/5)", value)
+	}
+
+	focus, err := parseMinutes(focusPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid focus length: %w", err)
+	}
+
+	brk, err := parseMinutes(breakPart)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid break length: %w", err)
+	}
+
+	if focus <= 0 || brk < 0 {
+		return 0, 0, fmt.Errorf("focus must be positive and break can't be negative")
+	}
+
+	return focus, brk, nil
+}
+
+func parseMinutes(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if n, err := strconv.Atoi(value); err == nil {
+		return time.Duration(n) * time.Minute, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// runPomodoro keeps the process in the foreground and alternates focus
+// blocks (intervals) with breaks (paused time) until rounds are done or the
+// user interrupts. rounds <= 0 means no limit.
+func runPomodoro(sessionID int, start time.Time, focus, brk time.Duration, rounds int) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := models.Pomodoros.SetTimer(sessionID, focus, brk); err != nil {
+		return fmt.Errorf("failed to save pomodoro settings: %w", err)
+	}
+
+	ts := &data.TaskSession{ID: sessionID}
+	blockStart := start
+
+	for round := 1; rounds <= 0 || round <= rounds; round++ {
+		if round > 1 {
+			current, err := models.TaskSessions.GetByID(sessionID)
+			if err != nil {
+				return fmt.Errorf("failed to check session: %w", err)
+			}
+			running, err := models.TaskSessionIntervals.HasOpenInterval(sessionID)
+			if err != nil {
+				return fmt.Errorf("failed to check session intervals: %w", err)
+			}
+			if current.EndedAt != nil || running {
+				fmt.Println("The session was stopped or resumed elsewhere. Leaving pomodoro mode.")
+				return nil
+			}
+
+			if _, err := models.TaskSessionIntervals.StartNew(sessionID, blockStart); err != nil {
+				return fmt.Errorf("failed to start focus block: %w", err)
+			}
+		}
+
+		p, err := models.Pomodoros.Start(sessionID, blockStart)
+		if err != nil {
+			return fmt.Errorf("failed to record pomodoro: %w", err)
+		}
+
+		focusEnd := blockStart.Add(focus)
+		fmt.Printf("🍅 Focus #%d until %s (Ctrl+C to abandon)\n", round, focusEnd.In(data.Location()).Format("15:04"))
+
+		if !waitUntil(ctx, focusEnd) {
+			now := time.Now()
+			if _, err := models.TaskSessionIntervals.End(ts, now); err != nil {
+				return fmt.Errorf("failed to pause session: %w", err)
+			}
+			if err := models.Pomodoros.Finish(p.ID, now, data.PomodoroAbandoned); err != nil {
+				return fmt.Errorf("failed to update pomodoro: %w", err)
+			}
+			fmt.Println("\n⚠️  Pomodoro abandoned. The session is paused; use `worklogger resume` or `worklogger stop`.")
+			return nil
+		}
+
+		// The session may have been paused or stopped from another terminal.
+		tsi, err := models.TaskSessionIntervals.End(ts, focusEnd)
+		if err != nil {
+			return fmt.Errorf("failed to end focus block: %w", err)
+		}
+		if tsi == nil {
+			if err := models.Pomodoros.Finish(p.ID, time.Now(), data.PomodoroAbandoned); err != nil {
+				return fmt.Errorf("failed to update pomodoro: %w", err)
+			}
+			fmt.Println("⚠️  The session was paused or stopped elsewhere. Pomodoro abandoned.")
+			return nil
+		}
+
+		if err := models.Pomodoros.Finish(p.ID, focusEnd, data.PomodoroCompleted); err != nil {
+			return fmt.Errorf("failed to update pomodoro: %w", err)
+		}
+
+		if rounds > 0 && round == rounds {
+			if _, err := models.TaskSessions.Stop(sessionID, focusEnd); err != nil {
+				return fmt.Errorf("failed to stop session: %w", err)
+			}
+			fmt.Printf("\a✅ Pomodoro #%d done. All %d rounds complete — session stopped.\n", round, rounds)
+			return nil
+		}
+
+		breakEnd := focusEnd.Add(brk)
+		fmt.Printf("\a✅ Pomodoro #%d done. Break until %s\n", round, breakEnd.In(data.Location()).Format("15:04"))
+
+		if !waitUntil(ctx, breakEnd) {
+			fmt.Println("\nStopped during a break. The session is paused; use `worklogger resume` or `worklogger stop`.")
+			return nil
+		}
+
+		fmt.Print("\a")
+		blockStart = breakEnd
+	}
+
+	return nil
+}
+
+// waitUntil blocks until t or until ctx is cancelled. It reports whether t
+// was reached.
+func waitUntil(ctx context.Context, t time.Time) bool {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,174 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	clientFlag string
+	remoteFlag string
+)
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage the projects tasks are grouped under",
+	Long: `Group tasks under projects, each with an optional client and git remote.
+
+Tasks started with --project <name> join that project, which is created on
+first use. Without --project, the project whose remote matches the
+repository's origin is used, both for new tasks and for which session is
+active.
+
+Example:
+  worklogger project add api --client Acme --remote git@github.com:acme/api.git
+  worklogger project list`,
+}
+
+// projectAddCmd represents the project add command
+var projectAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Create a project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := strings.TrimSpace(args[0])
+		if name == "" {
+			fmt.Println("⚠️  The project needs a name.")
+			return
+		}
+
+		if _, err := models.Projects.GetByName(name); err == nil {
+			fmt.Printf("⚠️  Project %s already exists.\n", name)
+			return
+		} else if !errors.Is(err, data.ErrRecordNotFound) {
+			cmd.PrintErrf("failed to check projects: %v\n", err)
+			return
+		}
+
+		remote := remoteFlag
+		if remote == "." {
+			r, err := data.GitRemote(".")
+			if err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
+			remote = r
+		}
+
+		project := &data.Project{Name: name, Client: clientFlag, Remote: remote}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		if err := models.Projects.InsertTx(tx, project); err != nil {
+			cmd.PrintErrf("failed to create project: %v\n", err)
+			return
+		}
+
+		// Claiming this repository's remote moves its running sessions to the
+		// project, where they are looked up from now on.
+		if origin, err := data.GitRemote("."); err == nil && project.Remote != "" && data.NormalizeRemote(origin) == project.Remote {
+			if err := models.TaskSessions.MoveOpenTX(tx, repositoryRoot(), project.Name); err != nil {
+				cmd.PrintErrf("failed to move sessions to the project: %v\n", err)
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		fmt.Printf("📁 Created project %s (#%d)\n", project.Name, project.ID)
+	},
+}
+
+// projectListCmd represents the project list command
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List projects with their total time",
+	Run: func(cmd *cobra.Command, args []string) {
+		totals, err := data.GetProjectTotals(db)
+		if err != nil {
+			cmd.PrintErrf("failed to get projects: %v\n", err)
+			return
+		}
+
+		if len(totals) == 0 || totals[0].ID == 0 {
+			fmt.Println("No projects yet. Create one with `worklogger project add <name>`.")
+			return
+		}
+
+		fmt.Printf("%-5s  %-20s  %-16s  %5s  %8s  %s\n", "ID", "PROJECT", "CLIENT", "TASKS", "HOURS", "REMOTE")
+		for _, p := range totals {
+			if p.ID == 0 {
+				continue
+			}
+			fmt.Printf("%-5d  %-20s  %-16s  %5d  %8.2f  %s\n",
+				p.ID, truncate(p.Name, 20), truncate(p.Client, 16), p.Tasks, p.Hours, p.Remote)
+		}
+	},
+}
+
+// projectRemoveCmd represents the project remove command
+var projectRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a project, keeping its tasks",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		project, err := models.Projects.GetByName(args[0])
+		if errors.Is(err, data.ErrRecordNotFound) {
+			fmt.Printf("⚠️  No project called %s.\n", args[0])
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to look up project: %v\n", err)
+			return
+		}
+
+		if err := models.Projects.Delete(project.ID); err != nil {
+			cmd.PrintErrf("failed to delete project: %v\n", err)
+			return
+		}
+
+		fmt.Printf("🗑  Deleted project %s. Its tasks are kept without a project.\n", project.Name)
+	},
+}
+
+// repositoryProject returns the project whose git remote matches the
+// repository's origin, or nil if there is none.
+func repositoryProject() *data.Project {
+	remote, err := data.GitRemote(".")
+	if err != nil {
+		return nil
+	}
+
+	project, err := models.Projects.GetByRemote(remote)
+	if err != nil {
+		return nil
+	}
+
+	return project
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectAddCmd, projectListCmd, projectRemoveCmd)
+
+	projectAddCmd.Flags().StringVar(&clientFlag, "client", "", "Client the project is for")
+	projectAddCmd.Flags().StringVar(&remoteFlag, "remote", "", `Git remote URL of the project, or "." for this repository's origin`)
+}
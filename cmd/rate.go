@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	rateClientFlag  string
+	rateProjectFlag string
+	rateTagFlag     string
+)
+
+// rateCmd represents the rate command
+var rateCmd = &cobra.Command{
+	Use:   "rate",
+	Short: "Manage hourly rates for invoices",
+	Long: `Set what an hour is billed at, by default or for a client, project or
+tag. A session is billed at the highest rate of its tags, else its
+project's rate, else its client's, else the default rate.
+
+Example:
+  worklogger rate set 90
+  worklogger rate set 120 --client Acme
+  worklogger rate set 150 --tag consulting
+  worklogger rate list
+  worklogger rate remove --client Acme`,
+}
+
+// rateSetCmd represents the rate set command
+var rateSetCmd = &cobra.Command{
+	Use:   "set <amount>",
+	Short: "Set an hourly rate",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		scope, name, err := rateScope(cmd)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		hourly, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || hourly < 0 {
+			fmt.Printf("⚠️  Invalid rate: %s\n", args[0])
+			return
+		}
+
+		if err := models.Rates.Set(scope, name, hourly); err != nil {
+			cmd.PrintErrf("failed to set rate: %v\n", err)
+			return
+		}
+
+		fmt.Printf("💰 %s: %.2f per hour\n", rateLabel(scope, name), hourly)
+	},
+}
+
+// rateListCmd represents the rate list command
+var rateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List hourly rates",
+	Run: func(cmd *cobra.Command, args []string) {
+		rates, err := models.Rates.GetAll()
+		if err != nil {
+			cmd.PrintErrf("failed to get rates: %v\n", err)
+			return
+		}
+
+		if len(rates) == 0 {
+			fmt.Println("No rates yet. Set a default with `worklogger rate set <amount>`.")
+			return
+		}
+
+		fmt.Printf("%-8s  %-30s  %10s\n", "SCOPE", "NAME", "HOURLY")
+		for _, r := range rates {
+			fmt.Printf("%-8s  %-30s  %10.2f\n", r.Scope, truncate(r.Name, 30), r.Hourly)
+		}
+	},
+}
+
+// rateRemoveCmd represents the rate remove command
+var rateRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove an hourly rate",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		scope, name, err := rateScope(cmd)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		err = models.Rates.Delete(scope, name)
+		if errors.Is(err, data.ErrRecordNotFound) {
+			fmt.Printf("⚠️  There is no %s.\n", strings.ToLower(rateLabel(scope, name)))
+			return
+		}
+		if err != nil {
+			cmd.PrintErrf("failed to remove rate: %v\n", err)
+			return
+		}
+
+		fmt.Printf("🗑  Removed the %s\n", strings.ToLower(rateLabel(scope, name)))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rateCmd)
+	rateCmd.AddCommand(rateSetCmd, rateListCmd, rateRemoveCmd)
+
+	for _, c := range []*cobra.Command{rateSetCmd, rateRemoveCmd} {
+		c.Flags().StringVar(&rateClientFlag, "client", "", "Rate for a client's projects")
+		c.Flags().StringVar(&rateProjectFlag, "project", "", "Rate for a project")
+		c.Flags().StringVar(&rateTagFlag, "tag", "", "Rate for sessions with a tag")
+	}
+}
+
+// rateScope reads which rate --client, --project or --tag points at; with
+// none of them it is the default rate.
+func rateScope(cmd *cobra.Command) (string, string, error) {
+	scope, name := data.RateDefault, ""
+	for _, f := range []struct{ flag, scope, value string }{
+		{"client", data.RateClient, rateClientFlag},
+		{"project", data.RateProject, rateProjectFlag},
+		{"tag", data.RateTag, rateTagFlag},
+	} {
+		if !cmd.Flags().Changed(f.flag) {
+			continue
+		}
+		if scope != data.RateDefault {
+			return "", "", errors.New("give at most one of --client, --project or --tag")
+		}
+		scope, name = f.scope, strings.TrimSpace(f.value)
+		if name == "" {
+			return "", "", fmt.Errorf("--%s needs a name", f.flag)
+		}
+	}
+
+	return scope, name, nil
+}
+
+func rateLabel(scope, name string) string {
+	if scope == data.RateDefault {
+		return "Default rate"
+	}
+	return fmt.Sprintf("Rate for %s %s", scope, name)
+}
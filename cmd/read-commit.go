@@ -12,6 +12,7 @@ import (
 
 var (
 	hashFlag, messageFlag, authorFlag, dateFlag string
+	repoFlag                                    string
 )
 
 // readCommitCmd represents the readCommit command
@@ -28,7 +29,12 @@ All flags are required:
   --hash      Commit hash
   --message   Commit message
   --author    Author of the commit
-  --date      Commit date (ISO 8601 or compatible format)`,
+  --date      Commit date (ISO 8601 or compatible format)
+
+The commit is attached to the active session of the repository it was made
+in: the one given with --repo, or the current directory's. --project
+overrides both. Files changed, insertions and deletions are read from that
+repository with git show --numstat.`,
 	Run: func(cmd *cobra.Command, args []string) {
 
 		if hashFlag == "" || messageFlag == "" || authorFlag == "" || dateFlag == "" {
@@ -36,7 +42,18 @@ All flags are required:
 			return
 		}
 
-		ts, err := models.TaskSessions.Get()
+		commitWorkspace := workspace
+		if repoFlag != "" && projectFlag == "" {
+			root, err := data.GitTopLevel(repoFlag)
+			if err != nil {
+				cmd.PrintErr(err)
+				fmt.Println()
+				return
+			}
+			commitWorkspace = repositoryWorkspace(repoFlag, root)
+		}
+
+		ts, err := models.TaskSessions.Get(commitWorkspace)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to check active session: %w", err))
 			fmt.Println()
@@ -56,6 +73,16 @@ All flags are required:
 			Date:      dateFlag,
 		}
 
+		dir := repoFlag
+		if dir == "" {
+			dir = "."
+		}
+		if stat, err := data.GitCommitStats(dir, hashFlag); err == nil {
+			commit.DiffStat = stat
+		} else {
+			fmt.Printf("⚠️  Couldn't read the diff stats of %s: %v\n", hashFlag, err)
+		}
+
 		if err := models.Commits.Create(commit); err != nil {
 			fmt.Printf("Failed to insert commit: %v\n", err)
 			return
@@ -72,4 +99,5 @@ func init() {
 	readCommitCmd.Flags().StringVar(&messageFlag, "message", "", "Git commit message")
 	readCommitCmd.Flags().StringVar(&authorFlag, "author", "", "Commit author")
 	readCommitCmd.Flags().StringVar(&dateFlag, "date", "", "Commit date")
+	readCommitCmd.Flags().StringVar(&repoFlag, "repo", "", "Repository the commit was made in (default is the current one)")
 }
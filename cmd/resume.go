@@ -4,18 +4,28 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
 )
 
 // resumeCmd represents the resume command
 var resumeCmd = &cobra.Command{
 	Use:   "resume",
 	Short: "Resume a paused work session",
-	Long:  `Resumes a previously paused task session if one exists.`,
+	Long: `Resumes a previously paused task session if one exists.
+
+Use --at if you actually got back to work earlier, e.g. --at "10m ago".`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ts, err := models.TaskSessions.Get()
+		at, err := resolveAt(atFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		ts, err := models.TaskSessions.Get(workspace)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to check active task: %w", err))
 			fmt.Println()
@@ -39,17 +49,23 @@ var resumeCmd = &cobra.Command{
 			return
 		}
 
-		tsi, err := models.TaskSessionIntervals.StartNew(ts.ID)
+		tsi, err := models.TaskSessionIntervals.StartNew(ts.ID, at)
+		if errors.Is(err, data.ErrBeforeActivity) || errors.Is(err, data.ErrOverlappingInterval) {
+			fmt.Println("⚠️  That time overlaps time that is already logged. Pick a later --at.")
+			return
+		}
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to resume task: %w", err))
 			fmt.Println()
 			return
 		}
 
-		fmt.Printf("Session resumed at %v\n", tsi.StartTime.Format("2006-01-02 15:04:05"))
+		fmt.Printf("Session resumed at %v\n", tsi.StartTime.In(data.Location()).Format("2006-01-02 15:04:05"))
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(resumeCmd)
+
+	resumeCmd.Flags().StringVar(&atFlag, "at", "", `When work resumed, e.g. "14:30" or "10m ago" (default now)`)
 }
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -19,6 +20,9 @@ var (
 	dsn     string
 	cfgFile string
 	models  data.Models
+
+	projectFlag string
+	workspace   string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -46,7 +50,16 @@ to quickly create a Cobra application.`,
 			return
 		}
 
-		config.Init()
+		// Settings can be managed before and outside of a repository.
+		if cmd.HasParent() && cmd.Parent() == configCmd {
+			return
+		}
+
+		// status runs on every prompt render; it needs neither the keyring
+		// nor the environment.
+		if cmd.Name() != "status" {
+			config.Init()
+		}
 
 		if err := checkInitialization(); err != nil {
 			fmt.Printf("%v\n", err)
@@ -58,6 +71,12 @@ to quickly create a Cobra application.`,
 			db = data.NewSQLiteDB(dsn)
 			models = data.NewModels(db)
 		}
+
+		workspace = resolveWorkspace(projectFlag)
+
+		if !skipStaleCheck[cmd.Name()] {
+			recoverStaleSession()
+		}
 	},
 
 	PersistentPostRun: func(cmd *cobra.Command, args []string) {
@@ -83,9 +102,19 @@ func init() {
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.worklogger.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.worklogger.yaml; .worklogger/config.yaml in the repository overrides it)")
 
 	rootCmd.PersistentFlags().StringVar(&dsn, "dsn", ".worklogger/db.sqlite", "SQLite database file path")
+	rootCmd.PersistentFlags().StringVar(&projectFlag, "project", "", "Project to track (default is the project with this repository's remote, or the repository itself)")
+	rootCmd.PersistentFlags().StringVar(&autoCapFlag, "auto-cap", "", "End a stale session without asking: last-commit, default or off")
+	rootCmd.PersistentFlags().String("timezone", "", "IANA timezone to report and display times in (default is the system timezone)")
+	viper.BindPFlag("timezone", rootCmd.PersistentFlags().Lookup("timezone"))
+	rootCmd.PersistentFlags().Duration("round", 0, "Round reported time to this step, e.g. 15m (stored times stay exact)")
+	rootCmd.PersistentFlags().String("round-mode", "", "Round reported time up, down or nearest (default nearest)")
+	rootCmd.PersistentFlags().String("round-per", "", "Round each interval, session or day (default session)")
+	viper.BindPFlag("rounding.step", rootCmd.PersistentFlags().Lookup("round"))
+	viper.BindPFlag("rounding.mode", rootCmd.PersistentFlags().Lookup("round-mode"))
+	viper.BindPFlag("rounding.per", rootCmd.PersistentFlags().Lookup("round-per"))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -114,6 +143,47 @@ func initConfig() {
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
+
+	mergeRepoConfig()
+
+	applyTimezone()
+	applyRounding()
+	loadSchedule()
+}
+
+// applyTimezone sets the timezone days are reported and times displayed in
+// from the timezone setting. Timestamps are always stored in UTC, so changing
+// it only changes how they are read back.
+func applyTimezone() {
+	name := viper.GetString("timezone")
+	if name == "" {
+		return
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  unknown timezone %q, using the system timezone\n", name)
+		return
+	}
+
+	data.SetLocation(loc)
+}
+
+// applyRounding sets how reports round time from the rounding settings.
+// Without a step, reports show exact time.
+func applyRounding() {
+	step := viper.GetDuration("rounding.step")
+	if step == 0 {
+		return
+	}
+
+	r, err := data.ParseRounding(step, viper.GetString("rounding.mode"), viper.GetString("rounding.per"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ignoring rounding: %v\n", err)
+		return
+	}
+
+	data.SetRounding(r)
 }
 
 func checkInitialization() error {
@@ -134,3 +204,46 @@ func checkInitialization() error {
 
 	return nil
 }
+
+// resolveWorkspace names the project sessions are tracked under: the
+// --project value if given, otherwise the project claiming the repository's
+// origin remote, then the repository itself.
+func resolveWorkspace(project string) string {
+	if project != "" {
+		return project
+	}
+
+	return repositoryWorkspace(".", repositoryRoot())
+}
+
+// repositoryWorkspace returns the name of the project claiming the origin
+// remote of the repository at dir, or root if no project does. A claimed
+// repository shares its sessions with --project <name>.
+func repositoryWorkspace(dir, root string) string {
+	remote, err := data.GitRemote(dir)
+	if err != nil {
+		return root
+	}
+
+	project, err := models.Projects.GetByRemote(remote)
+	if err != nil {
+		return root
+	}
+
+	return project.Name
+}
+
+// repositoryRoot is the workspace of a repository no project claims: its
+// top-level directory, falling back to the working directory outside of
+// git.
+func repositoryRoot() string {
+	if root, err := data.GitTopLevel("."); err == nil {
+		return root
+	}
+
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+
+	return ""
+}
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+const (
+	atEndStop  = "stop"
+	atEndPause = "pause"
+	atEndOff   = "off"
+)
+
+var schedulePollFlag time.Duration
+
+// scheduleCmd represents the schedule command
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Show your working hours",
+	Long: `Show the working hours configured in ~/.worklogger.yaml:
+
+  schedule:
+    days: [mon, tue, wed, thu, fri]
+    start: "09:00"
+    end: "17:30"
+    lunch: "12:30-13:30"
+    at_end: stop          # stop, pause or off
+    remind_every: 30m     # 0 turns reminders off
+  notify:
+    command: notify-send worklogger "$WORKLOGGER_MESSAGE"
+
+Time worked outside these hours, lunch included, is reported as overtime.
+Run 'worklogger schedule run' in the background to end sessions when the day
+is over and to be reminded when nothing is running during working hours.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sched := data.CurrentSchedule()
+		if sched == nil {
+			fmt.Println("No working hours configured. Set schedule.start and schedule.end in ~/.worklogger.yaml.")
+			return
+		}
+
+		fmt.Println("🗓  Working hours:")
+		fmt.Printf("   Days:   %s\n", strings.Join(scheduleDays(sched), ", "))
+		fmt.Printf("   Hours:  %s–%s\n", formatClock(sched.Start), formatClock(sched.End))
+		if sched.LunchEnd > 0 {
+			fmt.Printf("   Lunch:  %s–%s\n", formatClock(sched.LunchStart), formatClock(sched.LunchEnd))
+		}
+		fmt.Printf("   At end: %s\n", scheduleAtEnd())
+
+		if every := viper.GetDuration("schedule.remind_every"); every > 0 {
+			fmt.Printf("   Remind: every %v\n", every)
+		} else {
+			fmt.Println("   Remind: off")
+		}
+
+		if command := viper.GetString("notify.command"); command != "" {
+			fmt.Printf("   Notify: %s\n", command)
+		}
+	},
+}
+
+// scheduleRunCmd represents the schedule run command
+var scheduleRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "End sessions at the end of the day and remind you to start one",
+	Long: `Run in the background and enforce the working hours.
+
+When the working day ends, a session that is still running is stopped (or
+paused, with schedule.at_end: pause) at the end time. During working hours
+you are reminded every schedule.remind_every when no session is running.
+
+Reminders go to the command in notify.command, run with sh; the message is
+in $WORKLOGGER_MESSAGE and $1. Without one they are printed.
+
+Example:
+  worklogger schedule run &`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sched := data.CurrentSchedule()
+		if sched == nil {
+			fmt.Println("⚠️  No working hours configured. See 'worklogger schedule --help'.")
+			return
+		}
+
+		if schedulePollFlag <= 0 {
+			fmt.Println("⚠️  --poll must be a positive duration.")
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("🗓  Keeping %s–%s (checking every %v)\n", formatClock(sched.Start), formatClock(sched.End), schedulePollFlag)
+
+		ticker := time.NewTicker(schedulePollFlag)
+		defer ticker.Stop()
+
+		var lastReminder time.Time
+
+		for {
+			if err := checkSchedule(sched, time.Now(), &lastReminder); err != nil {
+				cmd.PrintErrf("schedule: %v\n", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				fmt.Println("Stopped scheduler.")
+				return
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+	scheduleCmd.AddCommand(scheduleRunCmd)
+
+	scheduleRunCmd.Flags().DurationVar(&schedulePollFlag, "poll", time.Minute, "How often to check the schedule")
+
+	viper.SetDefault("schedule.at_end", atEndStop)
+	viper.SetDefault("schedule.remind_every", 30*time.Minute)
+}
+
+// loadSchedule reads the working hours from the config. They stay unset when
+// schedule.start and schedule.end are missing or invalid.
+func loadSchedule() {
+	start, end := viper.GetString("schedule.start"), viper.GetString("schedule.end")
+	if start == "" && end == "" {
+		return
+	}
+
+	sched, err := data.ParseSchedule(
+		viper.GetStringSlice("schedule.days"),
+		start,
+		end,
+		viper.GetString("schedule.lunch"),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  ignoring schedule: %v\n", err)
+		return
+	}
+
+	data.SetSchedule(sched)
+}
+
+// checkSchedule ends a session still running past the end of the working day
+// and reminds the user when nothing is running during working hours.
+func checkSchedule(sched *data.Schedule, now time.Time, lastReminder *time.Time) error {
+	ts, err := models.TaskSessions.Get(workspace)
+	if err != nil {
+		return fmt.Errorf("failed to check active session: %w", err)
+	}
+
+	var open *data.TaskSessionInterval
+	if ts != nil {
+		open, err = models.TaskSessionIntervals.GetOpen(ts.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check session intervals: %w", err)
+		}
+	}
+
+	if open != nil {
+		// Only intervals begun before the day ended; starting again in the
+		// evening is the user's call.
+		if end, ok := sched.LastDayEnd(now); ok && open.StartTime.Before(end) {
+			return endWorkingDay(ts, end)
+		}
+		return nil
+	}
+
+	every := viper.GetDuration("schedule.remind_every")
+	if every <= 0 || !sched.InHours(now) || now.Sub(*lastReminder) < every {
+		return nil
+	}
+
+	message := "No session is running. Start one with 'worklogger start'."
+	if ts != nil {
+		message = fmt.Sprintf("Session #%d is paused. Pick it up with 'worklogger resume'.", ts.ID)
+	}
+
+	*lastReminder = now
+	return notify(message)
+}
+
+// endWorkingDay stops or pauses the session at the end of the working day,
+// as schedule.at_end says.
+func endWorkingDay(ts *data.TaskSession, end time.Time) error {
+	at := end.In(data.Location()).Format("15:04")
+
+	switch scheduleAtEnd() {
+	case atEndOff:
+		return nil
+
+	case atEndPause:
+		tsi, err := models.TaskSessionIntervals.End(ts, end)
+		if err != nil {
+			return fmt.Errorf("failed to pause session: %w", err)
+		}
+		if tsi == nil {
+			return nil
+		}
+
+		if err := models.TaskSessionIntervals.SetPauseReason(tsi.ID, data.PauseOffHours); err != nil {
+			return fmt.Errorf("failed to record pause reason: %w", err)
+		}
+
+		err = models.SessionEvents.Create(&data.SessionEvent{
+			SessionID:  ts.ID,
+			Kind:       data.EventSchedulePause,
+			OccurredAt: end,
+			Detail:     "working day ended",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record pause: %w", err)
+		}
+
+		fmt.Printf("⏸  Working day ended — paused session #%d at %s\n", ts.ID, at)
+		return notify(fmt.Sprintf("Working day ended at %s. Session #%d is paused.", at, ts.ID))
+
+	default:
+		if err := capSession(ts.ID, end, data.EventScheduleStop, "working day ended"); err != nil {
+			return fmt.Errorf("failed to stop session: %w", err)
+		}
+
+		fmt.Printf("🏁 Working day ended — stopped session #%d at %s\n", ts.ID, at)
+		return notify(fmt.Sprintf("Working day ended at %s. Session #%d is stopped.", at, ts.ID))
+	}
+}
+
+func scheduleAtEnd() string {
+	switch policy := viper.GetString("schedule.at_end"); policy {
+	case atEndPause, atEndOff:
+		return policy
+	default:
+		return atEndStop
+	}
+}
+
+// notify sends a message through notify.command, or prints it when no
+// command is configured.
+func notify(message string) error {
+	command := viper.GetString("notify.command")
+	if command == "" {
+		fmt.Printf("🔔 %s\n", message)
+		return nil
+	}
+
+	c := exec.Command("sh", "-c", command, "worklogger", message)
+	c.Env = append(os.Environ(), "WORKLOGGER_MESSAGE="+message)
+
+	if output, err := c.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify command failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func scheduleDays(sched *data.Schedule) []string {
+	var days []time.Weekday
+	for day := range sched.Days {
+		days = append(days, day)
+	}
+
+	// Monday first.
+	sort.Slice(days, func(i, j int) bool {
+		return (days[i]+6)%7 < (days[j]+6)%7
+	})
+
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = day.String()[:3]
+	}
+	return names
+}
+
+func formatClock(offset time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(offset/time.Hour), int(offset%time.Hour/time.Minute))
+}
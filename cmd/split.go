@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split <session-id>",
+	Short: "Cut a session in two at a point in time",
+	Long: `Split a session that covered two pieces of work.
+
+The session ends at --at and a new session starts at the same moment. An
+interval running across the cut is divided between the two, and commits,
+pomodoros and automatic pauses from --at onwards move to the new session.
+The new session keeps the mode, tags and KPIs; notes stay with the first.
+
+Use --task or --task-id to give the second half a different task; without
+either it stays on the same task. Times without a date are taken to be on
+the day the session started.
+
+Examples:
+  worklogger split 12 --at 14:30 --task "Hotfix for checkout"
+  worklogger split 12 --at "2026-10-17 16:05" --task-id 4`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Printf("⚠️  Invalid session ID: %s\n", args[0])
+			return
+		}
+
+		if atFlag == "" {
+			fmt.Println("⚠️  No split time provided. Use --at to specify one.")
+			return
+		}
+
+		ts, err := models.TaskSessions.GetByID(id)
+		if err != nil {
+			if errors.Is(err, data.ErrRecordNotFound) {
+				fmt.Printf("Session #%d not found.\n", id)
+				return
+			}
+			cmd.PrintErr(fmt.Errorf("failed to load session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		at, err := parseTime(atFlag, ts.StartedAt.In(data.Location()))
+		if err != nil {
+			fmt.Printf("⚠️  --at: %s\n", err.Error())
+			return
+		}
+
+		task, err := models.Tasks.Get(ts.TaskID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load task: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if taskFlag != "" || taskIDFlag != 0 {
+			task, err = resolveTask(taskIDFlag, taskFlag)
+			if err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		next, moved, err := models.SplitSession(tx, ts, task, at)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		fmt.Printf("✂️  Split session #%d at %s\n", ts.ID, at.In(data.Location()).Format("2006-01-02 15:04:05"))
+		fmt.Printf("   New session #%d for task: %s\n", next.ID, task.Description)
+		if moved > 0 {
+			fmt.Printf("   Moved %d commit(s) to the new session\n", moved)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().StringVar(&atFlag, "at", "", `Where to cut the session, e.g. "14:30"`)
+	splitCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task for the second half")
+	splitCmd.Flags().IntVar(&taskIDFlag, "task-id", 0, "ID of an existing task for the second half")
+}
@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+	"github.com/tormgibbs/worklogger/tui"
+)
+
+const (
+	autoCapLastCommit = "last-commit"
+	autoCapDefault    = "default"
+	autoCapOff        = "off"
+)
+
+var autoCapFlag string
+
+// skipStaleCheck lists commands that must never stop to ask about a stale
+// session: they run unattended or on every prompt render.
+var skipStaleCheck = map[string]bool{
+	"status":     true,
+	"watch":      true,
+	"run":        true,
+	"completion": true,
+	"__complete": true,
+}
+
+// recoverStaleSession looks for an active session that has been running, or
+// sat paused, for longer than stale.threshold or across stale.day_end, and
+// caps it. The user is asked how to cap it when attached to a terminal;
+// otherwise the --auto-cap policy (or stale.auto_cap from the config) decides.
+func recoverStaleSession() {
+	active, err := models.TaskSessions.Get(workspace)
+	if err != nil || active == nil {
+		return
+	}
+
+	ts, err := models.TaskSessions.GetByID(active.ID)
+	if err != nil || ts == nil {
+		return
+	}
+
+	intervals, err := models.TaskSessionIntervals.GetAllForSession(active.ID)
+	if err != nil {
+		return
+	}
+
+	since, running := staleSince(ts.StartedAt, intervals)
+	now := time.Now()
+
+	cutoff, err := staleCutoff(since, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  stale session check: %v\n", err)
+		return
+	}
+	if !now.After(cutoff) {
+		return
+	}
+
+	// A paused session tracked nothing after it was paused, so that is
+	// where it ends by default, and commits since then don't extend it.
+	state := "paused"
+	capAt := since
+	var lastCommit *time.Time
+	if running {
+		state = "running"
+		capAt = cutoff
+		lastCommit = lastCommitTime(active.ID, since, now)
+	}
+
+	policy := autoCapFlag
+	if policy == "" {
+		policy = viper.GetString("stale.auto_cap")
+	}
+
+	var at time.Time
+
+	switch {
+	case policy == autoCapOff:
+		return
+
+	case policy == autoCapLastCommit:
+		at = capAt
+		if lastCommit != nil {
+			at = *lastCommit
+		}
+
+	case policy == autoCapDefault:
+		at = capAt
+
+	case policy != "":
+		fmt.Fprintf(os.Stderr, "⚠️  unknown --auto-cap policy %q (use %s, %s or %s)\n",
+			policy, autoCapLastCommit, autoCapDefault, autoCapOff)
+		return
+
+	case !isInteractive():
+		fmt.Fprintf(os.Stderr, "⚠️  Session #%d has been %s since %s. Run worklogger in a terminal or pass --auto-cap to end it.\n",
+			active.ID, state, since.In(data.Location()).Format("2006-01-02 15:04"))
+		return
+
+	default:
+		chosen, ok := askStaleCap(active.ID, state, since, capAt, lastCommit)
+		if !ok {
+			return
+		}
+		at = chosen
+	}
+
+	if err := capSession(active.ID, at, data.EventStaleCap, "stale session ended on next command"); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  failed to end stale session #%d: %v\n", active.ID, err)
+		return
+	}
+
+	fmt.Printf("✂️  Stale session #%d ended at %s\n", active.ID, at.In(data.Location()).Format("2006-01-02 15:04:05"))
+}
+
+// staleSince returns when the session's current state began: the start of
+// its running interval, or the end of its last one if it is paused. A session
+// without intervals counts as paused since it started.
+func staleSince(started time.Time, intervals []*data.TaskSessionInterval) (time.Time, bool) {
+	if len(intervals) == 0 {
+		return started, false
+	}
+
+	last := intervals[len(intervals)-1]
+	if last.EndTime == nil {
+		return last.StartTime, true
+	}
+
+	return *last.EndTime, false
+}
+
+// staleCutoff returns the latest time a session that started running or was
+// paused at start can reasonably still be left that way: start plus
+// stale.threshold, or the first stale.day_end after start if that comes
+// sooner.
+func staleCutoff(start, now time.Time) (time.Time, error) {
+	threshold := viper.GetDuration("stale.threshold")
+	if threshold <= 0 {
+		threshold = 12 * time.Hour
+	}
+	cutoff := start.Add(threshold)
+
+	if dayEnd := viper.GetString("stale.day_end"); dayEnd != "" {
+		end, err := parseTime(dayEnd, start.In(data.Location()))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("stale.day_end: %w", err)
+		}
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		if end.Before(cutoff) {
+			cutoff = end
+		}
+	}
+
+	return cutoff, nil
+}
+
+// lastCommitTime returns the newest commit on the session made while its
+// interval was running, or nil if there is none.
+func lastCommitTime(sessionID int, start, now time.Time) *time.Time {
+	commits, err := models.Commits.GetBySession(sessionID)
+	if err != nil {
+		return nil
+	}
+
+	var latest *time.Time
+	for _, c := range commits {
+		date, err := data.ParseCommitDate(c.Date)
+		if err != nil || date.Before(start) || date.After(now) {
+			continue
+		}
+		if latest == nil || date.After(*latest) {
+			latest = &date
+		}
+	}
+
+	return latest
+}
+
+func askStaleCap(sessionID int, state string, start, cutoff time.Time, lastCommit *time.Time) (time.Time, bool) {
+	const layout = "Mon 2006-01-02 15:04"
+
+	var (
+		options []string
+		times   []time.Time
+	)
+
+	if lastCommit != nil {
+		options = append(options, fmt.Sprintf("End at the last commit (%s)", lastCommit.In(data.Location()).Format(layout)))
+		times = append(times, *lastCommit)
+	}
+
+	options = append(options, fmt.Sprintf("End at the default time (%s)", cutoff.In(data.Location()).Format(layout)))
+	times = append(times, cutoff)
+
+	chooseIndex := len(options)
+	options = append(options, "Enter an end time", "Keep it running")
+
+	title := fmt.Sprintf("⚠️  Session #%d has been %s since %s. Did you forget to stop it?",
+		sessionID, state, start.In(data.Location()).Format(layout))
+
+	index, err := tui.RunChoiceUI(title, options)
+	if err != nil || index < 0 || index > chooseIndex {
+		return time.Time{}, false
+	}
+
+	if index < chooseIndex {
+		return times[index], true
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Print(`End time (e.g. "18:30" or "2026-10-17 18:30", empty to cancel): `)
+		line, _ := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return time.Time{}, false
+		}
+
+		at, err := parseTime(line, start.In(data.Location()))
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			continue
+		}
+		if at.Before(start) || at.After(time.Now()) {
+			fmt.Printf("⚠️  The end time must be between %s and now.\n", start.In(data.Location()).Format(layout))
+			continue
+		}
+
+		return at, true
+	}
+}
+
+// capSession closes the running interval and the session at the given time,
+// abandons any running pomodoro and records why as a session event, all in one
+// transaction.
+func capSession(sessionID int, at time.Time, kind, detail string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := models.TaskSessionIntervals.EndTX(tx, sessionID, at); err != nil {
+		return err
+	}
+
+	if _, err := models.TaskSessions.StopTX(tx, sessionID, at); err != nil {
+		return err
+	}
+
+	if err := models.Pomodoros.AbandonRunningTX(tx, sessionID, at); err != nil {
+		return err
+	}
+
+	err = models.SessionEvents.CreateTX(tx, &data.SessionEvent{
+		SessionID:  sessionID,
+		Kind:       kind,
+		OccurredAt: at,
+		Detail:     detail,
+	})
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
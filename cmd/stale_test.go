@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+func TestStaleSince(t *testing.T) {
+	started := time.Date(2025, 3, 3, 8, 0, 0, 0, time.UTC)
+	nine := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	noon := time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC)
+	one := time.Date(2025, 3, 3, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		intervals   []*data.TaskSessionInterval
+		want        time.Time
+		wantRunning bool
+	}{
+		{name: "no intervals", want: started},
+		{
+			name:        "running",
+			intervals:   []*data.TaskSessionInterval{{StartTime: nine, EndTime: &noon}, {StartTime: one}},
+			want:        one,
+			wantRunning: true,
+		},
+		{
+			name:      "paused",
+			intervals: []*data.TaskSessionInterval{{StartTime: nine, EndTime: &noon}},
+			want:      noon,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, running := staleSince(started, tt.intervals)
+			if !got.Equal(tt.want) || running != tt.wantRunning {
+				t.Fatalf("got %v, %v, want %v, %v", got, running, tt.want, tt.wantRunning)
+			}
+		})
+	}
+}
+
+func TestStaleCutoff(t *testing.T) {
+	previous := data.Location()
+	t.Cleanup(func() { data.SetLocation(previous) })
+
+	loc := time.FixedZone("UTC+2", 2*3600)
+	data.SetLocation(loc)
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	local := func(value string) time.Time {
+		t.Helper()
+
+		at, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return at
+	}
+
+	tests := []struct {
+		name      string
+		threshold string
+		dayEnd    string
+		start     string
+		want      string
+	}{
+		{name: "default threshold", start: "2025-03-03 09:00", want: "2025-03-03 21:00"},
+		{name: "threshold", threshold: "4h", start: "2025-03-03 09:00", want: "2025-03-03 13:00"},
+		{name: "day end first", dayEnd: "18:00", start: "2025-03-03 09:00", want: "2025-03-03 18:00"},
+		{name: "threshold first", threshold: "2h", dayEnd: "18:00", start: "2025-03-03 09:00", want: "2025-03-03 11:00"},
+		{name: "after day end", dayEnd: "18:00", start: "2025-03-03 19:00", want: "2025-03-04 07:00"},
+		{name: "day end tomorrow", threshold: "24h", dayEnd: "18:00", start: "2025-03-03 19:00", want: "2025-03-04 18:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			viper.Set("stale.threshold", tt.threshold)
+			viper.Set("stale.day_end", tt.dayEnd)
+
+			start := local(tt.start)
+			got, err := staleCutoff(start, start.Add(48*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := local(tt.want); !got.Equal(want) {
+				t.Fatalf("got %v, want %v", got.In(loc), want)
+			}
+		})
+	}
+
+	viper.Set("stale.day_end", "late")
+	if _, err := staleCutoff(local("2025-03-03 09:00"), local("2025-03-04 09:00")); err == nil {
+		t.Fatal("an invalid stale.day_end was accepted")
+	}
+}
@@ -2,36 +2,87 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tormgibbs/worklogger/data"
 )
 
 var (
-	taskFlag string
+	taskFlag    string
+	taskIDFlag  int
+	newTaskFlag bool
 
 	modeFlag  string
 	tagFlags  []string
 	kpiFlags  []string
 	notesFlag string
+	atFlag    string
+
+	nonBillableFlag bool
+
+	pomodoroFlag string
+	roundsFlag   int
+	estimateFlag time.Duration
+	parentFlag   string
 )
 
 // startCmd represents the start command
 var startCmd = &cobra.Command{
-	Use:   "start",
+	Use:   "start [task-id]",
 	Short: "Start a new task session",
 	Long: `Begin tracking a new task session with optional tags and KPIs.
 
-This will create a new task and log the start time. If you already
-have an active session, you'll need to stop or pause it first.
+If a task with the same description already exists, the new session is
+added to it so time adds up per task. Similar descriptions bring up a
+picker; pass a backlog task ID (or --task-id) to continue a specific task or
+--new-task to always create a fresh one. The task is marked in progress.
+
+If you already have an active session, you'll need to stop or pause it
+first.
+
+Use --at to backdate the start, e.g. --at "20m ago" or --at 09:15.
+
+Use --estimate to say how long the task should take, e.g. --estimate 3h.
+'stop' and 'log' then show the time left or the overrun across all of the
+task's sessions.
+
+New tasks join the project named by --project, created on first use, or the
+project whose git remote matches the repository. Use --parent with a task ID
+or description to make the task a subtask; time rolls up to the parent in
+'summary', 'export' and the studio.
+
+With --pomodoro focus/break (minutes) the command keeps running and times
+focus blocks for you: each block is an interval, each break is paused time,
+and completed or abandoned pomodoros are counted per session. Press Ctrl+C
+to abandon the current block.
 
 Example:
-  worklogger start --task "Write documentation`,
+  worklogger start --task "Write documentation"
+  worklogger start 12
+  worklogger start --task-id 4 --at "15m ago"
+  worklogger start --task "Write report" --pomodoro 25/5 --rounds 4`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		if taskFlag == "" {
+		if len(args) == 1 {
+			id, err := strconv.Atoi(args[0])
+			if err != nil || id <= 0 {
+				fmt.Printf("⚠️  %q is not a task ID. Use --task for a description.\n", args[0])
+				return
+			}
+			if taskIDFlag != 0 && taskIDFlag != id {
+				fmt.Println("⚠️  Give the task ID either as an argument or with --task-id, not both.")
+				return
+			}
+			taskIDFlag = id
+		}
+
+		if taskFlag == "" && taskIDFlag == 0 {
 			fmt.Println("⚠️  No task provided. Use --task or -t to specify one.")
 			return
 		}
@@ -41,7 +92,38 @@ Example:
 			return
 		}
 
-		ts, err := models.TaskSessions.Get()
+		if estimateFlag < 0 {
+			fmt.Println("⚠️  --estimate can't be negative.")
+			return
+		}
+
+		startedAt, err := resolveAt(atFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		var focus, brk time.Duration
+		if pomodoroFlag != "" {
+			if atFlag != "" {
+				fmt.Println("⚠️  --pomodoro can't be combined with --at.")
+				return
+			}
+			focus, brk, err = parsePomodoro(pomodoroFlag)
+			if err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
+			startedAt = startedAt.Truncate(time.Second)
+		}
+
+		plan, err := loadTaskPlan()
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		ts, err := activeSession(plan)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to check active task: %w", err))
 			fmt.Println()
@@ -53,17 +135,21 @@ Example:
 			return
 		}
 
-		description := taskFlag
-
-		task := &data.Task{
-			Description: description,
+		task := &data.Task{Description: taskFlag}
+		if !newTaskFlag {
+			task, err = resolveTask(taskIDFlag, taskFlag)
+			if err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
 		}
 
 		session := &data.TaskSession{
-			StartedAt: time.Now(),
+			StartedAt: startedAt,
 			Mode:      getSessionMode(),
 			Notes:     notesFlag,
 			Synced:    false,
+			Workspace: workspace,
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -76,11 +162,36 @@ Example:
 		}
 		defer tx.Rollback()
 
+		if err := plan.apply(tx, task); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
 		if err := models.CreateTask(tx, task, session); err != nil {
+			if errors.Is(err, data.ErrOverlappingInterval) {
+				fmt.Println("⚠️  That start time overlaps time that is already logged.")
+				return
+			}
 			cmd.PrintErr(err)
 			return
 		}
 
+		if task.Status != data.TaskInProgress {
+			if err := models.Tasks.SetStatusTx(tx, task.ID, data.TaskInProgress); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark task in progress: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if nonBillableFlag {
+			if err := models.TaskSessions.SetBillableTX(tx, session.ID, false); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark session non-billable: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
 		if len(tagFlags) > 0 {
 			if err := models.SessionTags.Create(tx, session.ID, tagFlags); err != nil {
 				cmd.PrintErr(err)
@@ -100,7 +211,7 @@ Example:
 			return
 		}
 
-		formattedTime := task.CreatedAt.Format("2006-01-02 15:04:05")
+		formattedTime := session.StartedAt.In(data.Location()).Format("2006-01-02 15:04:05")
 		fmt.Printf("Session started at %s for task: %s\n", formattedTime, task.Description)
 
 		if session.Mode == "org" {
@@ -115,6 +226,22 @@ Example:
 		if notesFlag != "" {
 			fmt.Printf("   Notes: %s\n", notesFlag)
 		}
+		if plan.project != nil && task.ProjectID == plan.project.ID {
+			fmt.Printf("   Project: %s\n", plan.project.Name)
+		}
+		if plan.parent != nil {
+			fmt.Printf("   Parent: #%d %s\n", plan.parent.ID, plan.parent.Description)
+		}
+		if task.Estimate > 0 {
+			fmt.Printf("   Estimate: %s\n", formatDuration(task.Estimate))
+		}
+
+		if pomodoroFlag != "" {
+			if err := runPomodoro(session.ID, startedAt, focus, brk, roundsFlag); err != nil {
+				cmd.PrintErr(err)
+				fmt.Println()
+			}
+		}
 	},
 }
 
@@ -122,14 +249,24 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 
 	startCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task description to start logging")
+	startCmd.Flags().IntVar(&taskIDFlag, "task-id", 0, "ID of an existing task to continue")
+	startCmd.Flags().BoolVar(&newTaskFlag, "new-task", false, "Always create a new task, even if a similar one exists")
 
 	startCmd.Flags().StringVar(&modeFlag, "mode", "", "Session mode: personal or org")
 	startCmd.Flags().StringSliceVar(&tagFlags, "tag", nil, "Tags to associate with the session")
 	startCmd.Flags().StringSliceVar(&kpiFlags, "kpi", nil, "KPIs to associate with the session (required for org mode)")
 	startCmd.Flags().StringVar(&notesFlag, "notes", "", "Notes for this session")
+	startCmd.Flags().BoolVar(&nonBillableFlag, "non-billable", false, "Keep this session off invoices")
+	startCmd.Flags().StringVar(&pomodoroFlag, "pomodoro", "", "Run timed focus/break blocks in minutes, e.g. 25/5")
+	startCmd.Flags().IntVar(&roundsFlag, "rounds", 0, "Stop after this many pomodoros (default: until interrupted)")
+	startCmd.Flags().StringVar(&atFlag, "at", "", `When the session started, e.g. "14:30" or "20m ago" (default now)`)
+	startCmd.Flags().DurationVar(&estimateFlag, "estimate", 0, "How long the task should take, e.g. 3h or 90m")
+	startCmd.Flags().StringVar(&parentFlag, "parent", "", "Parent task ID or description, making this task a subtask")
 }
 
 func validateModeAndFlags() error {
+	applySessionDefaults()
+
 	mode := getSessionMode()
 
 	if mode == "org" && len(kpiFlags) == 0 {
@@ -140,15 +277,55 @@ func validateModeAndFlags() error {
 		return fmt.Errorf("mode must be 'personal' or 'org', got: %s", mode)
 	}
 
-	return nil
+	if err := checkOrgKPIs(mode, kpiFlags); err != nil {
+		return err
+	}
+
+	return checkCatalog(tagFlags, kpiFlags)
 }
 
+// getSessionMode returns the --mode flag, or session.mode from the config.
 func getSessionMode() string {
 	if modeFlag != "" {
 		return modeFlag
 	}
 
-	// TODO: Read from config file (.orcta.yaml)
-	// For now, default to personal
+	if mode := viper.GetString("session.mode"); mode != "" {
+		return mode
+	}
+
 	return "personal"
 }
+
+// applySessionDefaults uses session.tags and session.kpis from the config
+// when no --tag or --kpi was given.
+func applySessionDefaults() {
+	if len(tagFlags) == 0 {
+		tagFlags = nonEmpty(viper.GetStringSlice("session.tags"))
+	}
+	if len(kpiFlags) == 0 {
+		kpiFlags = nonEmpty(viper.GetStringSlice("session.kpis"))
+	}
+}
+
+// checkRequiredKPIs makes sure kpis include every KPI listed in
+// org.required_kpis.
+func checkRequiredKPIs(kpis []string) error {
+	have := make(map[string]bool, len(kpis))
+	for _, kpi := range kpis {
+		have[kpi] = true
+	}
+
+	var missing []string
+	for _, kpi := range nonEmpty(viper.GetStringSlice("org.required_kpis")) {
+		if !have[kpi] {
+			missing = append(missing, kpi)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("organization mode requires these KPIs: %s (use --kpi)", strings.Join(missing, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	statusFormatFlag string
+	statusJSONFlag   bool
+)
+
+// sessionStatus is what status prints, and the data --format templates and
+// --json see.
+type sessionStatus struct {
+	Active        bool          `json:"active"`
+	ID            int           `json:"session_id,omitempty"`
+	Task          string        `json:"task,omitempty"`
+	Project       string        `json:"project,omitempty"`
+	Mode          string        `json:"mode,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	KPIs          []string      `json:"kpis,omitempty"`
+	State         string        `json:"state"`
+	StartedAt     *time.Time    `json:"started_at,omitempty"`
+	ActiveTime    time.Duration `json:"-"`
+	PausedTime    time.Duration `json:"-"`
+	ActiveSeconds int64         `json:"active_seconds"`
+	PausedSeconds int64         `json:"paused_seconds"`
+}
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the active session",
+	Long: `Show what is being tracked right now in this repository.
+
+--json prints the status as a JSON object. --format renders a Go template
+with the fields .Task, .Project, .Mode, .Tags, .KPIs, .State ("running" or
+"paused"), .StartedAt, .ActiveTime and .PausedTime, and the functions
+duration and join. Nothing is printed with --format when no session is
+active, so it can go straight into a shell prompt.
+
+Examples:
+  worklogger status
+  worklogger status --json
+  worklogger status --format '{{.Task}} {{duration .ActiveTime}}{{if eq .State "paused"}} ⏸{{end}}'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		status, err := loadStatus()
+		if err != nil {
+			cmd.PrintErr(err)
+			fmt.Println()
+			return
+		}
+
+		switch {
+		case statusJSONFlag:
+			encoder := json.NewEncoder(os.Stdout)
+			if err := encoder.Encode(status); err != nil {
+				cmd.PrintErr(err)
+				fmt.Println()
+			}
+
+		case statusFormatFlag != "":
+			tmpl, err := template.New("status").Funcs(template.FuncMap{
+				"duration": formatDuration,
+				"join":     strings.Join,
+			}).Parse(statusFormatFlag)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("invalid --format: %w", err))
+				fmt.Println()
+				return
+			}
+
+			if !status.Active {
+				return
+			}
+
+			if err := tmpl.Execute(os.Stdout, status); err != nil {
+				cmd.PrintErr(fmt.Errorf("invalid --format: %w", err))
+				fmt.Println()
+			}
+
+		default:
+			printStatus(status)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringVar(&statusFormatFlag, "format", "", "Go template to render the status with")
+	statusCmd.Flags().BoolVar(&statusJSONFlag, "json", false, "Print the status as JSON")
+}
+
+func loadStatus() (*sessionStatus, error) {
+	status := &sessionStatus{State: "idle"}
+
+	active, err := models.TaskSessions.Get(workspace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check active session: %w", err)
+	}
+	if active == nil {
+		return status, nil
+	}
+
+	ts, err := models.TaskSessions.GetByID(active.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	task, err := models.Tasks.Get(ts.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task: %w", err)
+	}
+
+	tags, err := models.SessionTags.GetBySession(ts.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	kpis, err := models.SessionKPI.GetBySession(ts.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load KPIs: %w", err)
+	}
+
+	running, err := models.TaskSessionIntervals.HasOpenInterval(ts.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check session intervals: %w", err)
+	}
+
+	activeTime, pausedTime, _, err := models.TaskSessions.GetDurations(ts.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate durations: %w", err)
+	}
+
+	status.Active = true
+	status.ID = ts.ID
+	status.Task = task.Description
+	status.Project = ts.Workspace
+	status.Mode = ts.Mode
+	status.Tags = tags
+	status.KPIs = kpis
+	status.StartedAt = &ts.StartedAt
+	status.ActiveTime = activeTime
+	status.PausedTime = pausedTime
+	status.ActiveSeconds = int64(activeTime.Seconds())
+	status.PausedSeconds = int64(pausedTime.Seconds())
+
+	status.State = "paused"
+	if running {
+		status.State = "running"
+	}
+
+	return status, nil
+}
+
+func printStatus(status *sessionStatus) {
+	if !status.Active {
+		fmt.Println("No active session.")
+		return
+	}
+
+	icon := "🟢 Running"
+	if status.State == "paused" {
+		icon = "⏸  Paused"
+	}
+
+	fmt.Printf("%s: %s (session #%d)\n", icon, status.Task, status.ID)
+	fmt.Printf("   Started: %s\n", status.StartedAt.In(data.Location()).Format("2006-01-02 15:04:05"))
+	fmt.Printf("   Mode:    %s\n", status.Mode)
+	if len(status.Tags) > 0 {
+		fmt.Printf("   Tags:    %s\n", strings.Join(status.Tags, ", "))
+	}
+	if len(status.KPIs) > 0 {
+		fmt.Printf("   KPIs:    %s\n", strings.Join(status.KPIs, ", "))
+	}
+	fmt.Printf("  🟢 Active: %s\n", formatDuration(status.ActiveTime))
+	fmt.Printf("  🛑 Paused: %s\n", formatDuration(status.PausedTime))
+}
@@ -4,11 +4,15 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
 )
 
+var doneFlag bool
+
 // stopCmd represents the stop command
 var stopCmd = &cobra.Command{
 	Use:   "stop",
@@ -18,10 +22,20 @@ var stopCmd = &cobra.Command{
 It will close any running interval and record the session's end time.
 A summary of the session's durations (active, paused, total) will be printed.
 
+Use --at if you actually stopped earlier, e.g. --at "20m ago".
+Use --done to mark the task as done in the backlog as well.
+
 Example:
-  worklogger stop`,
+  worklogger stop
+  worklogger stop --at 17:45`,
 	Run: func(cmd *cobra.Command, args []string) {
-		ts, err := models.TaskSessions.Get()
+		at, err := resolveAt(atFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		ts, err := models.TaskSessions.Get(workspace)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to check active session: %w", err))
 			fmt.Println()
@@ -33,14 +47,22 @@ Example:
 			return
 		}
 
-		_, err = models.TaskSessionIntervals.End(ts)
+		_, err = models.TaskSessionIntervals.End(ts, at)
+		if errors.Is(err, data.ErrBeforeActivity) {
+			fmt.Println("⚠️  The session was resumed after that time. Pick a later --at.")
+			return
+		}
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to close running interval: %w", err))
 			fmt.Println()
 			return
 		}
 
-		stoppedSession, err := models.TaskSessions.Stop(ts.ID)
+		stoppedSession, err := models.TaskSessions.Stop(ts.ID, at)
+		if errors.Is(err, data.ErrBeforeActivity) {
+			fmt.Println("⚠️  There is logged activity after that time. Pick a later --at.")
+			return
+		}
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to stop session: %w", err))
 			fmt.Println()
@@ -52,6 +74,11 @@ Example:
 			return
 		}
 
+		if err := models.Pomodoros.AbandonRunning(ts.ID, at); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to update pomodoros: %w", err))
+			fmt.Println()
+		}
+
 		active, paused, total, err := models.TaskSessions.GetDurations(ts.ID)
 		if err != nil {
 			cmd.PrintErr(fmt.Errorf("failed to calculate session durations: %w", err))
@@ -59,19 +86,64 @@ Example:
 			return
 		}
 
-		formattedTime := stoppedSession.EndedAt.Format("2006-01-02 15:04:05")
+		formattedTime := stoppedSession.EndedAt.In(data.Location()).Format("2006-01-02 15:04:05")
 
 		fmt.Printf("Session stopped at %v.\n", formattedTime)
 		fmt.Println("Session summary:")
 		fmt.Printf("  ⏱️  Total:  %v\n", total)
 		fmt.Printf("  🟢 Active: %v\n", active)
 		fmt.Printf("  🛑 Paused: %v\n", paused)
+
+		pauses, err := models.TaskSessionIntervals.PausesBySession(ts.ID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load pause reasons: %w", err))
+			fmt.Println()
+		}
+		for _, p := range pauses {
+			fmt.Printf("     • %s: %v\n", p.Reason, p.Paused)
+		}
+
+		task, err := models.Tasks.Get(stoppedSession.TaskID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load task: %w", err))
+			fmt.Println()
+		} else if doneFlag {
+			if err := models.Tasks.SetStatus(task.ID, data.TaskDone); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark task done: %w", err))
+				fmt.Println()
+			} else {
+				fmt.Printf("  ✅ Task #%d is done: %s\n", task.ID, task.Description)
+			}
+		}
+
+		if task != nil && task.Estimate > 0 {
+			spent, err := models.Tasks.ActiveTime(task.ID)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to total task time: %w", err))
+				fmt.Println()
+			} else {
+				fmt.Printf("  🎯 Estimate: %s\n", estimateProgress(task.Estimate, spent))
+			}
+		}
+
+		completed, abandoned, err := models.Pomodoros.CountBySession(ts.ID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to count pomodoros: %w", err))
+			fmt.Println()
+			return
+		}
+		if completed+abandoned > 0 {
+			fmt.Printf("  🍅 Pomodoros: %d completed, %d abandoned\n", completed, abandoned)
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(stopCmd)
 
+	stopCmd.Flags().StringVar(&atFlag, "at", "", `When the session ended, e.g. "17:45" or "20m ago" (default now)`)
+	stopCmd.Flags().BoolVar(&doneFlag, "done", false, "Mark the task as done")
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/tormgibbs/worklogger/web/server"
 )
 
@@ -13,15 +14,17 @@ var studioCmd = &cobra.Command{
 	Long: `The "studio" command launches the Worklogger web interface 
 for logging and viewing your work data via the browser.`,
 	Run: func(cmd *cobra.Command, args []string) {
+	addr := viper.GetString("server.address")
+
 	go func() {
-		err := browser.OpenURL(server.Addr)
+		err := browser.OpenURL(server.URL(addr))
 		if err != nil {
 			cmd.PrintErr("Failed to open browser:", err)
 			return
 		}
 	}()
 
-	server.Serve(db)
+	server.Serve(db, addr)
 	},
 }
 
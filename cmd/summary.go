@@ -32,10 +32,86 @@ and productivity score based on the data in your local database.`,
 		}
 
 		fmt.Println("📊 Summary Stats:")
+		if data.CurrentRounding() != nil {
+			fmt.Printf("• Rounding: %s (stored times are exact)\n", stats.Rounding)
+		}
 		fmt.Printf("• Today's Hours: %.2f hrs (%s%+.2f%%%s)\n", stats.TodayHours.Value, color(stats.TodayHours.Change), stats.TodayHours.Change, reset)
 		fmt.Printf("• Week Hours: %.2f hrs (%s%+.2f%%%s)\n", stats.WeekHours.Value, color(stats.WeekHours.Change), stats.WeekHours.Change, reset)
 		fmt.Printf("• Sessions Today: %.0f (%s%+.2f%%%s)\n", stats.SessionsToday.Value, color(stats.SessionsToday.Change), stats.SessionsToday.Change, reset)
 		fmt.Printf("• Productivity Score: %.2f%% (%s%+.2f%%%s)\n", stats.ProductivityScore.Value, color(stats.ProductivityScore.Change), stats.ProductivityScore.Change, reset)
+		if data.CurrentSchedule() != nil {
+			fmt.Printf("• Overtime This Week: %.2f hrs (%s%+.2f%%%s)\n", stats.WeekOvertime.Value, color(-stats.WeekOvertime.Change), stats.WeekOvertime.Change, reset)
+		}
+
+		pomodoros, err := data.GetPomodoroStats(db)
+		if err != nil {
+			return fmt.Errorf("failed to get pomodoro stats: %w", err)
+		}
+
+		if len(pomodoros) > 0 {
+			fmt.Println()
+			fmt.Println("🍅 Pomodoros (last 7 days):")
+			for _, p := range pomodoros {
+				fmt.Printf("• %s: %d completed, %d abandoned\n", p.Date, p.Completed, p.Abandoned)
+			}
+		}
+
+		projects, err := data.GetProjectTotals(db)
+		if err != nil {
+			return fmt.Errorf("failed to get project totals: %w", err)
+		}
+
+		if len(projects) > 0 && projects[0].ID != 0 {
+			fmt.Println()
+			fmt.Println("📁 By Project:")
+			for _, p := range projects {
+				name := p.Name
+				if p.ID == 0 {
+					name = "(no project)"
+				} else if p.Client != "" {
+					name = fmt.Sprintf("%s (%s)", p.Name, p.Client)
+				}
+				fmt.Printf("• %s: %.2f hrs across %d tasks\n", name, p.Hours, p.Tasks)
+			}
+		}
+
+		parents, err := data.GetParentTotals(db)
+		if err != nil {
+			return fmt.Errorf("failed to get parent task totals: %w", err)
+		}
+
+		if len(parents) > 0 {
+			fmt.Println()
+			fmt.Println("🌳 By Parent Task:")
+			for _, p := range parents {
+				subtasks := "subtasks"
+				if p.Subtasks == 1 {
+					subtasks = "subtask"
+				}
+				fmt.Printf("• #%d %s: %.2f hrs (%.2f hrs own, %d %s)\n", p.ID, p.Task, p.Hours, p.OwnHours, p.Subtasks, subtasks)
+			}
+		}
+
+		estimates, err := data.GetEstimateReport(db)
+		if err != nil {
+			return fmt.Errorf("failed to get estimate report: %w", err)
+		}
+
+		if len(estimates.Tasks) > 0 {
+			fmt.Println()
+			fmt.Println("🎯 Estimate Accuracy:")
+			for _, e := range estimates.Tasks {
+				printEstimateStat(e)
+			}
+
+			if len(estimates.Tags) > 0 {
+				fmt.Println()
+				fmt.Println("🏷  By Tag:")
+				for _, e := range estimates.Tags {
+					printEstimateStat(e)
+				}
+			}
+		}
 
 		return nil
 	},
@@ -44,3 +120,11 @@ and productivity score based on the data in your local database.`,
 func init() {
 	rootCmd.AddCommand(summaryCmd)
 }
+
+// printEstimateStat prints estimated against actual time, with the overrun
+// in red and time to spare in green.
+func printEstimateStat(e *data.EstimateStat) {
+	change := (e.Ratio - 1) * 100
+	fmt.Printf("• %s: %s estimated, %s actual (%s%+.0f%%%s)\n",
+		e.Name, formatDuration(e.Estimate()), formatDuration(e.Actual()), color(-change), change, reset)
+}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var carryFlag bool
+
+// switchCmd represents the switch command
+var switchCmd = &cobra.Command{
+	Use:   "switch",
+	Short: "Stop the active session and start another task",
+	Long: `Move straight from the active session to a new task.
+
+The running interval is closed, the session is stopped and the new session
+starts in a single transaction, all at the same instant, so there is no gap
+and no overlap between the two. Use --carry to keep the current session's
+mode, tags and KPIs; --tag and --kpi add to whatever is carried.
+
+Example:
+  worklogger switch --task "Review PR #42"
+  worklogger switch --task-id 7 --carry`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if taskFlag == "" && taskIDFlag == 0 {
+			fmt.Println("⚠️  No task provided. Use --task or -t to specify one.")
+			return
+		}
+
+		plan, err := loadTaskPlan()
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		active, err := activeSession(plan)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to check active session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if active == nil {
+			fmt.Println("No active session to switch from. Use `worklogger start` instead.")
+			return
+		}
+
+		current, err := models.TaskSessions.GetByID(active.ID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to load active session: %w", err))
+			fmt.Println()
+			return
+		}
+
+		applySessionDefaults()
+
+		mode := getSessionMode()
+		tags := tagFlags
+		kpis := kpiFlags
+
+		if carryFlag {
+			if modeFlag == "" {
+				mode = current.Mode
+			}
+
+			carriedTags, err := models.SessionTags.GetBySession(current.ID)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to load tags: %w", err))
+				fmt.Println()
+				return
+			}
+			tags = mergeUnique(carriedTags, tagFlags)
+
+			carriedKPIs, err := models.SessionKPI.GetBySession(current.ID)
+			if err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to load KPIs: %w", err))
+				fmt.Println()
+				return
+			}
+			kpis = mergeUnique(carriedKPIs, kpiFlags)
+		}
+
+		if mode != "personal" && mode != "org" {
+			fmt.Printf("⚠️  mode must be 'personal' or 'org', got: %s\n", mode)
+			return
+		}
+
+		if mode == "org" && len(kpis) == 0 {
+			fmt.Println("⚠️  organization mode requires at least one KPI (use --kpi or --carry)")
+			return
+		}
+
+		if err := checkOrgKPIs(mode, kpis); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if err := checkCatalog(tagFlags, kpiFlags); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		task := &data.Task{Description: taskFlag}
+		if !newTaskFlag {
+			task, err = resolveTask(taskIDFlag, taskFlag)
+			if err != nil {
+				fmt.Printf("⚠️  %s\n", err.Error())
+				return
+			}
+		}
+
+		if task.ID == current.TaskID {
+			fmt.Println("That task is already active. Nothing to switch.")
+			return
+		}
+
+		next := &data.TaskSession{
+			Mode:      mode,
+			Notes:     notesFlag,
+			Synced:    false,
+			Workspace: workspace,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		if err := plan.apply(tx, task); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		at := time.Now().Truncate(time.Second)
+
+		if _, err := models.SwitchTask(tx, current.ID, task, next, at); err != nil {
+			cmd.PrintErr(err)
+			fmt.Println()
+			return
+		}
+
+		if task.Status != data.TaskInProgress {
+			if err := models.Tasks.SetStatusTx(tx, task.ID, data.TaskInProgress); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark task in progress: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if nonBillableFlag {
+			if err := models.TaskSessions.SetBillableTX(tx, next.ID, false); err != nil {
+				cmd.PrintErr(fmt.Errorf("failed to mark session non-billable: %w", err))
+				fmt.Println()
+				return
+			}
+		}
+
+		if len(tags) > 0 {
+			if err := models.SessionTags.Create(tx, next.ID, tags); err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+		}
+
+		if len(kpis) > 0 {
+			if err := models.SessionKPI.Create(tx, next.ID, kpis); err != nil {
+				cmd.PrintErr(err)
+				return
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		fmt.Printf("Switched at %s to task: %s\n", at.In(data.Location()).Format("2006-01-02 15:04:05"), task.Description)
+		if mode == "org" {
+			fmt.Printf("   Mode: %s\n", mode)
+		}
+		if len(tags) > 0 {
+			fmt.Printf("   Tags: %s\n", strings.Join(tags, ", "))
+		}
+		if len(kpis) > 0 {
+			fmt.Printf("   KPIs: %s\n", strings.Join(kpis, ", "))
+		}
+
+		activeTime, pausedTime, totalTime, err := models.TaskSessions.GetDurations(current.ID)
+		if err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to calculate session durations: %w", err))
+			fmt.Println()
+			return
+		}
+
+		fmt.Printf("Previous session #%d summary:\n", current.ID)
+		fmt.Printf("  ⏱️  Total:  %v\n", totalTime)
+		fmt.Printf("  🟢 Active: %v\n", activeTime)
+		fmt.Printf("  🛑 Paused: %v\n", pausedTime)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(switchCmd)
+
+	switchCmd.Flags().StringVarP(&taskFlag, "task", "t", "", "Task description to switch to")
+	switchCmd.Flags().IntVar(&taskIDFlag, "task-id", 0, "ID of an existing task to switch to")
+	switchCmd.Flags().BoolVar(&newTaskFlag, "new-task", false, "Always create a new task, even if a similar one exists")
+	switchCmd.Flags().BoolVar(&carryFlag, "carry", false, "Carry mode, tags and KPIs over from the current session")
+
+	switchCmd.Flags().StringVar(&modeFlag, "mode", "", "Session mode: personal or org")
+	switchCmd.Flags().StringSliceVar(&tagFlags, "tag", nil, "Tags to associate with the new session")
+	switchCmd.Flags().StringSliceVar(&kpiFlags, "kpi", nil, "KPIs to associate with the new session")
+	switchCmd.Flags().StringVar(&notesFlag, "notes", "", "Notes for the new session")
+	switchCmd.Flags().BoolVar(&nonBillableFlag, "non-billable", false, "Keep the new session off invoices")
+	switchCmd.Flags().DurationVar(&estimateFlag, "estimate", 0, "How long the new task should take, e.g. 3h or 90m")
+	switchCmd.Flags().StringVar(&parentFlag, "parent", "", "Parent task ID or description, making the new task a subtask")
+}
+
+// mergeUnique appends extra to base, skipping values already present.
+func mergeUnique(base, extra []string) []string {
+	seen := make(map[string]bool, len(base))
+	out := make([]string, 0, len(base)+len(extra))
+
+	for _, list := range [][]string{base, extra} {
+		for _, v := range list {
+			if !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+
+	return out
+}
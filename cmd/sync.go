@@ -11,6 +11,7 @@ import (
 var (
 	sessionID         int
 	taskDescription   string
+	syncTaskID        int
 	createNewSession  bool
 	leaveUnassociated bool
 )
@@ -23,27 +24,29 @@ var syncCmd = &cobra.Command{
 
 Examples:
   worklogger sync --new -d "Fix login bug"
+  worklogger sync --new --task-id 4
   worklogger sync --existing 12
   worklogger sync --unassociated
 
+With --new, an existing task with a matching description is reused.
 If no flag is passed, a prompt will guide you through the sync process.`,
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if createNewSession && taskDescription == "" {
-			return fmt.Errorf("when using --new, you must also provide --desc")
+		if createNewSession && taskDescription == "" && syncTaskID == 0 {
+			return fmt.Errorf("when using --new, you must also provide --desc or --task-id")
 		}
 		if createNewSession && sessionID > 0 {
 			return fmt.Errorf("--new and --existing can't be used together")
 		}
 		if leaveUnassociated {
-			if createNewSession || sessionID > 0 || taskDescription != "" {
-				return fmt.Errorf("--unassociated can't be used with --new, --existing, or --desc")
+			if createNewSession || sessionID > 0 || taskDescription != "" || syncTaskID > 0 {
+				return fmt.Errorf("--unassociated can't be used with --new, --existing, --desc or --task-id")
 			}
 		}
 		return nil
 	},
 
 	Run: func(cmd *cobra.Command, args []string) {
-		currentSession, err := models.TaskSessions.Get()
+		currentSession, err := models.TaskSessions.Get(workspace)
 		if err != nil {
 			cmd.PrintErrf("failed to check active session: %v\n", err)
 			return
@@ -73,6 +76,7 @@ func init() {
 
 	syncCmd.Flags().IntVarP(&sessionID, "existing", "e", 0, "Associate with existing session")
 	syncCmd.Flags().StringVarP(&taskDescription, "desc", "d", "", "Description for new task")
+	syncCmd.Flags().IntVar(&syncTaskID, "task-id", 0, "ID of an existing task for the new session")
 	syncCmd.Flags().BoolVarP(&createNewSession, "new", "n", false, "Create new session")
 	syncCmd.Flags().BoolVarP(&leaveUnassociated, "unassociated", "u", false, "Leave commits unassociated")
 
@@ -88,12 +92,18 @@ func handleActiveSessionSync(cmd *cobra.Command, session *data.TaskSession) {
 }
 
 func handleNewSessionSync(cmd *cobra.Command) {
-	if taskDescription == "" {
+	if taskDescription == "" && syncTaskID == 0 {
 		cmd.Println("Task description cannot be empty. Use --desc or -d to provide one.")
 		return
 	}
 
-	_, newSession, err := data.CreateTaskAndSession(db, taskDescription)
+	task, err := resolveTask(syncTaskID, taskDescription)
+	if err != nil {
+		cmd.PrintErrf("Failed to find task: %v\n", err)
+		return
+	}
+
+	_, newSession, err := data.CreateTaskAndSession(db, task, workspace)
 	if err != nil {
 		cmd.PrintErrf("Failed to create new session: %v\n", err)
 		return
@@ -177,7 +187,13 @@ func runInteractiveSync(cmd *cobra.Command) {
 			return
 		}
 
-		_, newSession, err := data.CreateTaskAndSession(db, desc)
+		task, err := resolveTask(0, desc)
+		if err != nil {
+			cmd.PrintErrf("Failed to find task:%v\n", err)
+			return
+		}
+
+		_, newSession, err := data.CreateTaskAndSession(db, task, workspace)
 		if err != nil {
 			cmd.PrintErrf("Failed to create new session:%v\n", err)
 			return
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	priorityFlag   string
+	taskStatusFlag string
+	taskAllFlag    bool
+)
+
+// statusOrder sorts the backlog: work in progress first, archived last.
+var statusOrder = map[string]int{
+	data.TaskInProgress: 0,
+	data.TaskTodo:       1,
+	data.TaskDone:       2,
+	data.TaskArchived:   3,
+}
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Plan work in a backlog of tasks",
+	Long: `Keep a backlog of tasks and move them through todo, in progress and
+done. Starting a session on a task marks it in progress; 'worklogger stop
+--done' marks it done.
+
+Example:
+  worklogger task add "Rate limiting" --priority high --estimate 4h
+  worklogger task list
+  worklogger start 12
+  worklogger stop --done`,
+}
+
+// taskAddCmd represents the task add command
+var taskAddCmd = &cobra.Command{
+	Use:   "add <description>",
+	Short: "Add a task to the backlog",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		description := strings.TrimSpace(strings.Join(args, " "))
+		if description == "" {
+			fmt.Println("⚠️  The task needs a description.")
+			return
+		}
+
+		priority, err := data.ParsePriority(priorityFlag)
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if estimateFlag < 0 {
+			fmt.Println("⚠️  --estimate can't be negative.")
+			return
+		}
+
+		plan, err := loadTaskPlan()
+		if err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		task := &data.Task{Description: description, Priority: priority}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+		defer tx.Rollback()
+
+		if err := plan.apply(tx, task); err != nil {
+			fmt.Printf("⚠️  %s\n", err.Error())
+			return
+		}
+
+		if err := models.Tasks.CreateTx(tx, task); err != nil {
+			cmd.PrintErr(fmt.Errorf("failed to add task: %w", err))
+			fmt.Println()
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			cmd.PrintErr(err)
+			return
+		}
+
+		fmt.Printf("📝 Added task #%d: %s (%s priority)\n", task.ID, task.Description, data.PriorityName(task.Priority))
+		fmt.Printf("   Start it with `worklogger start %d`.\n", task.ID)
+	},
+}
+
+// taskListCmd represents the task list command
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show the backlog",
+	Long: `Show open tasks, in progress first, then by priority, with the active
+time spent on each. Use --status to pick a state, or --all to include done
+and archived tasks.
+
+Continue a task with 'worklogger start <id>'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if taskStatusFlag != "" {
+			if _, ok := statusOrder[taskStatusFlag]; !ok {
+				fmt.Printf("⚠️  unknown status %q (use todo, in_progress, done or archived)\n", taskStatusFlag)
+				return
+			}
+		}
+
+		tasks, err := models.Tasks.GetAllWithTotals()
+		if err != nil {
+			cmd.PrintErrf("failed to get tasks: %v\n", err)
+			return
+		}
+
+		var backlog []*data.TaskSummary
+		for _, t := range tasks {
+			switch {
+			case taskStatusFlag != "":
+				if t.Status != taskStatusFlag {
+					continue
+				}
+			case !taskAllFlag:
+				if t.Status == data.TaskDone || t.Status == data.TaskArchived {
+					continue
+				}
+			}
+			backlog = append(backlog, t)
+		}
+
+		if len(backlog) == 0 {
+			fmt.Println("Nothing in the backlog. Add a task with `worklogger task add \"...\"`.")
+			return
+		}
+
+		sort.SliceStable(backlog, func(i, j int) bool {
+			a, b := backlog[i], backlog[j]
+			if statusOrder[a.Status] != statusOrder[b.Status] {
+				return statusOrder[a.Status] < statusOrder[b.Status]
+			}
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			return a.ID < b.ID
+		})
+
+		fmt.Printf("%-5s  %-12s  %-8s  %-40s  %-16s  %10s\n", "ID", "STATUS", "PRIORITY", "TASK", "PROJECT", "ACTIVE")
+		for _, t := range backlog {
+			project := t.Project
+			if project == "" {
+				project = "-"
+			}
+
+			active := formatDuration(t.ActiveTime)
+			if t.Estimate > 0 {
+				active += " / " + formatDuration(t.Estimate)
+			}
+
+			fmt.Printf("%-5d  %-12s  %-8s  %-40s  %-16s  %10s\n",
+				t.ID, strings.ReplaceAll(t.Status, "_", " "), data.PriorityName(t.Priority),
+				truncate(t.Description, 40), truncate(project, 16), active)
+		}
+	},
+}
+
+// tasksCmd is a shortcut for 'task list'.
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "Show the backlog (same as 'task list')",
+	Long:  taskListCmd.Long,
+	Run:   taskListCmd.Run,
+}
+
+// taskDoneCmd represents the task done command
+var taskDoneCmd = &cobra.Command{
+	Use:   "done <id>",
+	Short: "Mark a task as done",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setTaskStatus(cmd, args[0], data.TaskDone, "✅ Task #%d is done: %s\n")
+	},
+}
+
+// taskReopenCmd represents the task reopen command
+var taskReopenCmd = &cobra.Command{
+	Use:   "reopen <id>",
+	Short: "Put a done or archived task back in the backlog",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setTaskStatus(cmd, args[0], data.TaskTodo, "↩️  Task #%d is back in the backlog: %s\n")
+	},
+}
+
+// taskArchiveCmd represents the task archive command
+var taskArchiveCmd = &cobra.Command{
+	Use:   "archive <id>",
+	Short: "Hide a task from the backlog, keeping its time",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setTaskStatus(cmd, args[0], data.TaskArchived, "🗄  Archived task #%d: %s\n")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd, tasksCmd)
+	taskCmd.AddCommand(taskAddCmd, taskListCmd, taskDoneCmd, taskReopenCmd, taskArchiveCmd)
+
+	taskAddCmd.Flags().StringVarP(&priorityFlag, "priority", "p", "medium", "Priority: low, medium, high or urgent")
+	taskAddCmd.Flags().DurationVar(&estimateFlag, "estimate", 0, "How long the task should take, e.g. 3h or 90m")
+	taskAddCmd.Flags().StringVar(&parentFlag, "parent", "", "Parent task ID or description, making this task a subtask")
+
+	for _, c := range []*cobra.Command{taskListCmd, tasksCmd} {
+		c.Flags().StringVar(&taskStatusFlag, "status", "", "Only show tasks in this state: todo, in_progress, done or archived")
+		c.Flags().BoolVar(&taskAllFlag, "all", false, "Include done and archived tasks")
+	}
+}
+
+func setTaskStatus(cmd *cobra.Command, ref, status, message string) {
+	id, err := strconv.Atoi(ref)
+	if err != nil {
+		fmt.Printf("⚠️  %q is not a task ID.\n", ref)
+		return
+	}
+
+	task, err := models.Tasks.Get(id)
+	if errors.Is(err, data.ErrRecordNotFound) {
+		fmt.Printf("⚠️  Task #%d not found.\n", id)
+		return
+	}
+	if err != nil {
+		cmd.PrintErrf("failed to get task: %v\n", err)
+		return
+	}
+
+	if task.Status == status {
+		fmt.Printf("Task #%d is already %s.\n", id, strings.ReplaceAll(status, "_", " "))
+		return
+	}
+
+	if err := models.Tasks.SetStatus(id, status); err != nil {
+		cmd.PrintErrf("failed to update task: %v\n", err)
+		return
+	}
+
+	fmt.Printf(message, task.ID, task.Description)
+}
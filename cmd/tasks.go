@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/tormgibbs/worklogger/data"
+	"github.com/tormgibbs/worklogger/tui"
+)
+
+// resolveTask finds the task a new session should belong to. An ID always
+// wins. Otherwise an exact description match is reused, similar tasks are
+// offered in a picker when running in a terminal, and a new (unsaved) task
+// is returned when nothing fits.
+func resolveTask(id int, description string) (*data.Task, error) {
+	if id > 0 {
+		task, err := models.Tasks.Get(id)
+		if errors.Is(err, data.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task #%d not found", id)
+		}
+		return task, err
+	}
+
+	matches, err := models.Tasks.Search(description)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+
+	for _, task := range matches {
+		if strings.EqualFold(strings.TrimSpace(task.Description), strings.TrimSpace(description)) {
+			return task, nil
+		}
+	}
+
+	if len(matches) > 0 && isInteractive() {
+		if len(matches) > 10 {
+			matches = matches[:10]
+		}
+
+		title := fmt.Sprintf("Similar tasks found for %q — continue one of them?", description)
+		task, err := tui.RunTaskPickUI(title, matches)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil {
+			return task, nil
+		}
+	}
+
+	return &data.Task{Description: description}, nil
+}
+
+// taskPlan is what --estimate, --project and --parent say about the task a
+// session is logged on.
+type taskPlan struct {
+	estimate time.Duration
+	project  *data.Project
+	parent   *data.Task
+
+	// explicit is set when the project was named with --project rather than
+	// found through the repository's git remote.
+	explicit bool
+
+	// claimed is the repository's own workspace when a new project takes
+	// over its git remote. Sessions still open there move to the project.
+	claimed string
+}
+
+// loadTaskPlan looks up the project and parent task for a new session. The
+// project named by --project is created on first use; without --project the
+// project tracking the repository's origin remote is used, if any.
+func loadTaskPlan() (*taskPlan, error) {
+	plan := &taskPlan{estimate: estimateFlag}
+
+	if name := strings.TrimSpace(projectFlag); name != "" {
+		project, err := models.Projects.GetByName(name)
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			project = &data.Project{Name: name}
+			// Claim the repository's remote so later sessions here find the
+			// project without --project.
+			if remote, err := data.GitRemote("."); err == nil {
+				if _, err := models.Projects.GetByRemote(remote); errors.Is(err, data.ErrRecordNotFound) {
+					project.Remote = remote
+					plan.claimed = repositoryRoot()
+				}
+			}
+		case err != nil:
+			return nil, fmt.Errorf("failed to look up project: %w", err)
+		}
+		plan.project = project
+		plan.explicit = true
+	} else {
+		plan.project = repositoryProject()
+	}
+
+	if parentFlag != "" {
+		parent, err := findTask(parentFlag)
+		if err != nil {
+			return nil, fmt.Errorf("--parent: %w", err)
+		}
+		plan.parent = parent
+	}
+
+	return plan, nil
+}
+
+// activeSession returns the session running in this workspace. When plan
+// claims the repository for a new project, a session still running under
+// the repository's own workspace counts too, as the claim moves it over.
+func activeSession(plan *taskPlan) (*data.TaskSession, error) {
+	ts, err := models.TaskSessions.Get(workspace)
+	if err != nil || ts != nil || plan.claimed == "" {
+		return ts, err
+	}
+	return models.TaskSessions.Get(plan.claimed)
+}
+
+// apply gives task its estimate, project and parent within tx. New tasks
+// are created with them; existing tasks are updated. A project found through
+// the git remote only fills in a missing one, and subtasks without a project
+// take their parent's.
+func (p *taskPlan) apply(tx *sql.Tx, task *data.Task) error {
+	changed := false
+
+	if p.estimate > 0 {
+		task.Estimate = p.estimate
+		changed = true
+	}
+
+	if p.project != nil && p.project.ID == 0 {
+		if err := models.Projects.InsertTx(tx, p.project); err != nil {
+			return fmt.Errorf("failed to create project: %w", err)
+		}
+		fmt.Printf("📁 Created project %s\n", p.project.Name)
+
+		if p.claimed != "" && p.claimed != p.project.Name {
+			if err := models.TaskSessions.MoveOpenTX(tx, p.claimed, p.project.Name); err != nil {
+				return fmt.Errorf("failed to move sessions to the project: %w", err)
+			}
+		}
+	}
+
+	if p.project != nil && (p.explicit || task.ProjectID == 0) && task.ProjectID != p.project.ID {
+		task.ProjectID = p.project.ID
+		changed = true
+	}
+
+	if p.parent != nil && task.ParentID != p.parent.ID {
+		if task.ID > 0 {
+			cycle, err := models.Tasks.InAncestryTx(tx, p.parent.ID, task.ID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return fmt.Errorf("task #%d can't be a subtask of its own subtask #%d", task.ID, p.parent.ID)
+			}
+		}
+		task.ParentID = p.parent.ID
+		changed = true
+
+		if task.ProjectID == 0 && p.parent.ProjectID != 0 {
+			task.ProjectID = p.parent.ProjectID
+		}
+	}
+
+	if task.ID == 0 || !changed {
+		return nil
+	}
+
+	return models.Tasks.UpdatePlanTx(tx, task)
+}
+
+// findTask resolves a task given by ID or by its exact description.
+func findTask(ref string) (*data.Task, error) {
+	if id, err := strconv.Atoi(ref); err == nil {
+		task, err := models.Tasks.Get(id)
+		if errors.Is(err, data.ErrRecordNotFound) {
+			return nil, fmt.Errorf("task #%d not found", id)
+		}
+		return task, err
+	}
+
+	matches, err := models.Tasks.Search(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tasks: %w", err)
+	}
+
+	for _, task := range matches {
+		if strings.EqualFold(strings.TrimSpace(task.Description), strings.TrimSpace(ref)) {
+			return task, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no task called %q", ref)
+}
+
+// estimateProgress describes how much of an estimate has been used.
+func estimateProgress(estimate, spent time.Duration) string {
+	if left := estimate - spent; left >= 0 {
+		return fmt.Sprintf("%s of %s used, %s left", formatDuration(spent), formatDuration(estimate), formatDuration(left))
+	}
+	return fmt.Sprintf("%s of %s used, %s over", formatDuration(spent), formatDuration(estimate), formatDuration(spent-estimate))
+}
+
+// isInteractive reports whether stdin and stdout are terminals, so prompts
+// can be shown.
+func isInteractive() bool {
+	return term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stdout.Fd())
+}
+
+func formatDuration(d time.Duration) string {
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+}
+
+var clockLayouts = []string{
+	"15:04:05",
+	"15:04",
+}
+
+// parseTime parses an absolute timestamp in the configured timezone. Values
+// with only a clock time ("14:30") are placed on the same day as base.
+func parseTime(value string, base time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	loc := data.Location()
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			y, m, d := base.In(loc).Date()
+			return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), 0, loc), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognised time %q (use \"15:04\" or \"2006-01-02 15:04\")", value)
+}
+
+// parseTimeRange parses a "start..end" pair. Both ends are resolved against
+// base the same way parseTime does.
+func parseTimeRange(value string, base time.Time) (time.Time, time.Time, error) {
+	parts := strings.SplitN(value, "..", 2)
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range %q (use \"start..end\")", value)
+	}
+
+	start, err := parseTime(parts[0], base)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	end, err := parseTime(parts[1], start)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return start, end, nil
+}
+
+var durationUnits = strings.NewReplacer(
+	"hours", "h", "hour", "h", "hrs", "h", "hr", "h",
+	"minutes", "m", "minute", "m", "mins", "m", "min", "m",
+	"seconds", "s", "second", "s", "secs", "s", "sec", "s",
+	" ", "",
+)
+
+// parseAt parses the value of an --at flag. Besides everything parseTime
+// accepts it understands "now", relative offsets ("20m ago", "1 hour 5
+// minutes ago", "-15m") and day words ("yesterday 17:00", "today 9:30").
+// Clock times are taken to be today. An empty value means now.
+func parseAt(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch {
+	case value == "" || value == "now":
+		return now, nil
+
+	case strings.HasSuffix(value, " ago"):
+		d, err := time.ParseDuration(durationUnits.Replace(strings.TrimSuffix(value, " ago")))
+		if err != nil || d < 0 {
+			return time.Time{}, fmt.Errorf("unrecognised offset %q (use e.g. \"20m ago\")", value)
+		}
+		return now.Add(-d), nil
+
+	case strings.HasPrefix(value, "-"):
+		d, err := time.ParseDuration(durationUnits.Replace(value))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unrecognised offset %q (use e.g. \"-20m\")", value)
+		}
+		return now.Add(d), nil
+
+	case strings.HasPrefix(value, "yesterday"):
+		clock := strings.TrimSpace(strings.TrimPrefix(value, "yesterday"))
+		if clock == "" {
+			return time.Time{}, errors.New("\"yesterday\" needs a time, e.g. \"yesterday 17:00\"")
+		}
+		return parseTime(clock, now.AddDate(0, 0, -1))
+
+	case strings.HasPrefix(value, "today"):
+		clock := strings.TrimSpace(strings.TrimPrefix(value, "today"))
+		if clock == "" {
+			return now, nil
+		}
+		return parseTime(clock, now)
+	}
+
+	return parseTime(value, now)
+}
+
+// resolveAt parses an --at flag and rejects times in the future.
+func resolveAt(value string) (time.Time, error) {
+	now := time.Now()
+
+	at, err := parseAt(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if at.After(now) {
+		return time.Time{}, fmt.Errorf("--at %s is in the future", at.In(data.Location()).Format("2006-01-02 15:04:05"))
+	}
+
+	return at, nil
+}
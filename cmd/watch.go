@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tormgibbs/worklogger/data"
+)
+
+var (
+	idleFlag         time.Duration
+	pollFlag         time.Duration
+	eventSessionFlag int
+	eventLimitFlag   int
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Pause the active session automatically when you go idle",
+	Long: `Run in the background and watch the repository for activity.
+
+Activity means a file in the working tree was modified or a commit was made.
+When nothing happens for the --idle period, the running interval is ended at
+the time of the last activity. As soon as activity returns, the session is
+resumed. Sessions you paused yourself are never resumed automatically.
+
+Every automatic pause and resume is recorded; list them with
+'worklogger watch events'.
+
+Example:
+  worklogger watch --idle 15m &`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if idleFlag <= 0 || pollFlag <= 0 {
+			fmt.Println("⚠️  --idle and --poll must be positive durations.")
+			return
+		}
+
+		root, err := data.GitTopLevel(".")
+		if err != nil {
+			cmd.PrintErrf("%v\n", err)
+			return
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Printf("👀 Watching %s (idle after %v, checking every %v)\n", root, idleFlag, pollFlag)
+
+		ticker := time.NewTicker(pollFlag)
+		defer ticker.Stop()
+
+		for {
+			if err := checkIdle(root, time.Now()); err != nil {
+				cmd.PrintErrf("watch: %v\n", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				fmt.Println("Stopped watching.")
+				return
+			case <-ticker.C:
+			}
+		}
+	},
+}
+
+// watchEventsCmd represents the watch events command
+var watchEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List automatic pauses and resumes",
+	Run: func(cmd *cobra.Command, args []string) {
+		events, err := models.SessionEvents.GetAll(eventSessionFlag, eventLimitFlag)
+		if err != nil {
+			cmd.PrintErrf("failed to get events: %v\n", err)
+			return
+		}
+
+		if len(events) == 0 {
+			fmt.Println("No automatic pauses or resumes recorded.")
+			return
+		}
+
+		for _, e := range events {
+			icon := "⏸ "
+			if e.Kind == data.EventAutoResume {
+				icon = "▶️ "
+			}
+			fmt.Printf("%s %s  session #%-4d %-12s %s\n",
+				icon, e.OccurredAt.In(data.Location()).Format("2006-01-02 15:04:05"), e.SessionID, e.Kind, e.Detail)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	watchCmd.AddCommand(watchEventsCmd)
+
+	watchCmd.Flags().DurationVar(&idleFlag, "idle", 10*time.Minute, "Inactivity period after which the session is paused")
+	watchCmd.Flags().DurationVar(&pollFlag, "poll", 30*time.Second, "How often to check for activity")
+
+	watchEventsCmd.Flags().IntVar(&eventSessionFlag, "session", 0, "Only show events for this session")
+	watchEventsCmd.Flags().IntVar(&eventLimitFlag, "limit", 20, "Maximum number of events to show")
+}
+
+// checkIdle pauses the active session if the repository has been idle for
+// too long, or resumes it if the watcher paused it and activity is back.
+func checkIdle(root string, now time.Time) error {
+	ts, err := models.TaskSessions.Get(workspace)
+	if err != nil {
+		return fmt.Errorf("failed to check active session: %w", err)
+	}
+	if ts == nil {
+		return nil
+	}
+
+	last, err := data.LatestActivity(root)
+	if err != nil {
+		return err
+	}
+
+	open, err := models.TaskSessionIntervals.GetOpen(ts.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check session intervals: %w", err)
+	}
+
+	if open != nil {
+		// Starting or resuming the session counts as activity, so a fresh
+		// interval gets the full --idle before it is paused.
+		at := last
+		if at.Before(open.StartTime) {
+			at = open.StartTime
+		}
+
+		if now.Sub(at) < idleFlag {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		tsi, err := models.TaskSessionIntervals.EndTX(tx, ts.ID, at)
+		if err != nil {
+			return fmt.Errorf("failed to pause session: %w", err)
+		}
+		if tsi == nil {
+			// Paused by hand since the check above.
+			return nil
+		}
+
+		if err := models.TaskSessionIntervals.SetPauseReasonTX(tx, tsi.ID, data.PauseIdle); err != nil {
+			return fmt.Errorf("failed to record pause reason: %w", err)
+		}
+
+		event := &data.SessionEvent{
+			SessionID:  ts.ID,
+			Kind:       data.EventAutoPause,
+			OccurredAt: at,
+			Detail:     fmt.Sprintf("no activity for %v", now.Sub(at).Round(time.Second)),
+		}
+		if err := models.SessionEvents.CreateTX(tx, event); err != nil {
+			return fmt.Errorf("failed to record pause: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to pause session: %w", err)
+		}
+
+		fmt.Printf("⏸  Idle since %s — paused session #%d\n", at.In(data.Location()).Format("15:04:05"), ts.ID)
+		return nil
+	}
+
+	latest, err := models.SessionEvents.Latest(ts.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check session events: %w", err)
+	}
+
+	// Only resume sessions the watcher paused itself.
+	if latest == nil || latest.Kind != data.EventAutoPause || !last.After(latest.OccurredAt) {
+		return nil
+	}
+
+	intervals, err := models.TaskSessionIntervals.GetAllForSession(ts.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load intervals: %w", err)
+	}
+
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	// A different last pause means the user has resumed and paused by hand since.
+	lastInterval := intervals[len(intervals)-1]
+	if lastInterval.EndTime == nil || !lastInterval.EndTime.Equal(latest.OccurredAt) {
+		return nil
+	}
+
+	tsi, err := models.TaskSessionIntervals.StartNew(ts.ID, last)
+	if err != nil {
+		return fmt.Errorf("failed to resume session: %w", err)
+	}
+
+	event := &data.SessionEvent{
+		SessionID:  ts.ID,
+		Kind:       data.EventAutoResume,
+		OccurredAt: tsi.StartTime,
+		Detail:     fmt.Sprintf("activity after %v idle", tsi.StartTime.Sub(latest.OccurredAt).Round(time.Second)),
+	}
+	if err := models.SessionEvents.Create(event); err != nil {
+		return fmt.Errorf("failed to record resume: %w", err)
+	}
+
+	fmt.Printf("▶️  Activity at %s — resumed session #%d\n", tsi.StartTime.In(data.Location()).Format("15:04:05"), ts.ID)
+	return nil
+}
@@ -0,0 +1,156 @@
+package data
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitTopLevel returns the root directory of the git repository containing
+// dir.
+func GitTopLevel(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GitHooksDir returns the directory git runs hooks from for the repository
+// containing dir. It follows core.hooksPath and, in a linked worktree, the
+// main repository's hooks.
+func GitHooksDir(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "hooks")
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("not inside a git repository: %w", err)
+	}
+
+	hooks := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+
+	return filepath.Abs(hooks)
+}
+
+// GitRemote returns the URL of the origin remote of the repository
+// containing dir.
+func GitRemote(dir string) (string, error) {
+	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no origin remote: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// LatestActivity returns the most recent sign of work in the repository at
+// root: the newest modification time of any modified or untracked
+// (non-ignored) file, the last change to the index, or the last commit time,
+// whichever is later. The result is truncated to whole seconds, the precision
+// timestamps are stored at.
+func LatestActivity(root string) (time.Time, error) {
+	latest, err := latestFileChange(root)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if index, err := indexChangeTime(root); err == nil && index.After(latest) {
+		latest = index
+	}
+
+	if commit, err := latestCommitTime(root); err == nil && commit.After(latest) {
+		latest = commit
+	}
+
+	return latest.Truncate(time.Second), nil
+}
+
+// latestFileChange returns the newest modification time among the files that
+// differ from the index. Files that still match it have not been edited since
+// it was written, so they need no stat of their own.
+func latestFileChange(root string) (time.Time, error) {
+	cmd := exec.Command("git", "ls-files", "-z", "--modified", "--others", "--exclude-standard")
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to list repository files: %w", err)
+	}
+
+	var latest time.Time
+	for _, name := range bytes.Split(output, []byte{0}) {
+		// The database lives in .worklogger and changes on every command.
+		if len(name) == 0 || bytes.HasPrefix(name, []byte(".worklogger/")) {
+			continue
+		}
+
+		info, err := os.Lstat(filepath.Join(root, string(name)))
+		if err != nil {
+			// Deleted since the listing; nothing to compare.
+			continue
+		}
+
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
+
+// indexChangeTime returns when the repository's index was last written, which
+// git does on every add, commit, checkout and reset.
+func indexChangeTime(root string) (time.Time, error) {
+	cmd := exec.Command("git", "rev-parse", "--git-path", "index")
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	index := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(index) {
+		index = filepath.Join(root, index)
+	}
+
+	info, err := os.Stat(index)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
+
+func latestCommitTime(root string) (time.Time, error) {
+	cmd := exec.Command("git", "log", "-1", "--format=%ct")
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,95 @@
+package data
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLatestActivity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root := t.TempDir()
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "Test")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "test@example.com")
+	}
+
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+
+	touch := func(name string, at time.Time) {
+		t.Helper()
+
+		path := filepath.Join(root, name)
+		if err := os.Chtimes(path, at, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write := func(name, content string, at time.Time) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		touch(name, at)
+	}
+
+	check := func(want time.Time) {
+		t.Helper()
+
+		got, err := LatestActivity(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(want.Truncate(time.Second)) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	}
+
+	committed := time.Now().Add(-3 * time.Hour)
+	t.Setenv("GIT_COMMITTER_DATE", committed.Format(time.RFC3339))
+
+	git("init", "-q")
+	write("main.go", "package main\n", committed)
+	git("add", "main.go")
+	git("commit", "-q", "-m", "init")
+
+	// Only the index and the commit tell of activity so far.
+	touch(".git/index", committed.Add(-time.Hour))
+	check(committed)
+	index := time.Now().Add(-2 * time.Hour)
+	touch(".git/index", index)
+	check(index)
+
+	edited := time.Now().Add(-time.Hour)
+	write("main.go", "package main\n\nfunc main() {}\n", edited)
+	check(edited)
+
+	untracked := time.Now().Add(-30 * time.Minute)
+	write("notes.txt", "todo\n", untracked)
+	check(untracked)
+
+	// Ignored files and the database are no sign of work.
+	write(".gitignore", "*.log\n", untracked)
+	write("build.log", "ok\n", time.Now())
+	if err := os.Mkdir(filepath.Join(root, ".worklogger"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write(".worklogger/db.sqlite", "", time.Now())
+	check(untracked)
+}
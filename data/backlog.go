@@ -0,0 +1,109 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	TaskTodo       = "todo"
+	TaskInProgress = "in_progress"
+	TaskDone       = "done"
+	TaskArchived   = "archived"
+)
+
+const (
+	PriorityLow    = 1
+	PriorityMedium = 2
+	PriorityHigh   = 3
+	PriorityUrgent = 4
+)
+
+var priorityNames = map[int]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+// PriorityName names a priority level.
+func PriorityName(priority int) string {
+	if name, ok := priorityNames[priority]; ok {
+		return name
+	}
+	return fmt.Sprintf("p%d", priority)
+}
+
+// ParsePriority reads a priority given by name ("high") or level ("3").
+func ParsePriority(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for level, name := range priorityNames {
+		if value == name || value == fmt.Sprint(level) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q (use low, medium, high or urgent)", value)
+}
+
+// SetStatus moves a task to another state of the backlog.
+func (m TaskModel) SetStatus(taskID int, status string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.SetStatusTx(tx, taskID, status); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetStatusTx moves a task to another state of the backlog. Finishing a task
+// records when; any other state clears it.
+func (m TaskModel) SetStatusTx(tx *sql.Tx, taskID int, status string) error {
+	query := `
+		UPDATE tasks
+		SET
+			status = ?,
+			completed_at = CASE
+				WHEN ? = 'done' THEN COALESCE(completed_at, CURRENT_TIMESTAMP)
+				ELSE NULL
+			END
+		WHERE id = ?
+	`
+	result, err := tx.Exec(query, status, status, taskID)
+	if err != nil {
+		return err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ReleaseTx puts an in-progress task with no sessions left back to todo, as
+// when its only session moves to another task.
+func (m TaskModel) ReleaseTx(tx *sql.Tx, taskID int) error {
+	query := `
+		UPDATE tasks
+		SET status = 'todo'
+		WHERE id = ? AND status = 'in_progress'
+			AND NOT EXISTS (SELECT 1 FROM task_sessions WHERE task_id = tasks.id)
+	`
+	_, err := tx.Exec(query, taskID)
+	return err
+}
@@ -0,0 +1,419 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Rate scopes. A session is billed at the highest rate of its tags, else its
+// project's rate, else its client's, else the default rate.
+const (
+	RateDefault = "default"
+	RateClient  = "client"
+	RateProject = "project"
+	RateTag     = "tag"
+)
+
+var (
+	ErrNoRate          = errors.New("no hourly rate applies")
+	ErrAlreadyInvoiced = errors.New("session is already invoiced")
+)
+
+type RateModel struct {
+	DB *sql.DB
+}
+
+type Rate struct {
+	ID     int     `json:"id"`
+	Scope  string  `json:"scope"`
+	Name   string  `json:"name"`
+	Hourly float64 `json:"hourly"`
+}
+
+// Set adds or changes a rate. The default rate has no name.
+func (m RateModel) Set(scope, name string, hourly float64) error {
+	switch scope {
+	case RateDefault:
+		name = ""
+	case RateClient, RateProject, RateTag:
+	default:
+		return fmt.Errorf("unknown rate scope %q", scope)
+	}
+
+	query := `
+		INSERT INTO rates (scope, name, hourly_rate)
+		VALUES (?, ?, ?)
+		ON CONFLICT (scope, name) DO UPDATE SET hourly_rate = excluded.hourly_rate
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, scope, name, hourly)
+	return err
+}
+
+func (m RateModel) Delete(scope, name string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `DELETE FROM rates WHERE scope = ? AND name = ?`, scope, name)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetAll lists rates, the default first, then by scope and name.
+func (m RateModel) GetAll() ([]*Rate, error) {
+	query := `
+		SELECT id, scope, name, hourly_rate
+		FROM rates
+		ORDER BY
+			CASE scope WHEN 'default' THEN 0 WHEN 'client' THEN 1 WHEN 'project' THEN 2 ELSE 3 END,
+			name
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := make([]*Rate, 0)
+	for rows.Next() {
+		var r Rate
+		if err := rows.Scan(&r.ID, &r.Scope, &r.Name, &r.Hourly); err != nil {
+			return nil, err
+		}
+		rates = append(rates, &r)
+	}
+
+	return rates, rows.Err()
+}
+
+// rateTable looks up the rate a session is billed at.
+type rateTable map[string]float64
+
+func newRateTable(rates []*Rate) rateTable {
+	table := make(rateTable, len(rates))
+	for _, r := range rates {
+		table[r.Scope+":"+strings.ToLower(r.Name)] = r.Hourly
+	}
+	return table
+}
+
+func (t rateTable) lookup(client, project string, tags []string) (float64, bool) {
+	best, found := 0.0, false
+	for _, tag := range tags {
+		if rate, ok := t[RateTag+":"+strings.ToLower(tag)]; ok && (!found || rate > best) {
+			best, found = rate, true
+		}
+	}
+	if found {
+		return best, true
+	}
+
+	for _, key := range []string{RateProject + ":" + strings.ToLower(project), RateClient + ":" + strings.ToLower(client), RateDefault + ":"} {
+		if rate, ok := t[key]; ok {
+			return rate, true
+		}
+	}
+
+	return 0, false
+}
+
+type InvoiceModel struct {
+	DB *sql.DB
+}
+
+// Invoice bills a client for the active time of their sessions started
+// between From and To (exclusive).
+type Invoice struct {
+	ID        int            `json:"id"`
+	Number    string         `json:"number"`
+	Client    string         `json:"client"`
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Currency  string         `json:"currency"`
+	Hours     float64        `json:"hours"`
+	Total     float64        `json:"total"`
+	CreatedAt time.Time      `json:"created_at"`
+	Lines     []*InvoiceLine `json:"lines,omitempty"`
+
+	// Running is how many sessions in the period were left out because
+	// they are still running.
+	Running int `json:"-"`
+}
+
+type InvoiceLine struct {
+	SessionID   int       `json:"session_id"`
+	WorkedOn    time.Time `json:"worked_on"`
+	Description string    `json:"description"`
+	Project     string    `json:"project"`
+	Hours       float64   `json:"hours"`
+	Rate        float64   `json:"rate"`
+	Amount      float64   `json:"amount"`
+}
+
+// Draft prices the billable sessions of a client's projects started in
+// [from, to) that are not on an invoice yet. Running sessions are left out
+// and counted in Running. Nothing is recorded.
+func (m InvoiceModel) Draft(client string, from, to time.Time, currency string) (*Invoice, error) {
+	rates, err := RateModel{m.DB}.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rates: %w", err)
+	}
+	table := newRateTable(rates)
+
+	query := `
+		SELECT
+			ts.id,
+			ts.started_at,
+			t.description,
+			p.name,
+			p.client,
+			ts.ended_at IS NULL,
+			COALESCE(SUM(strftime('%s', tsi.end_time) - strftime('%s', tsi.start_time)), 0)
+		FROM task_sessions ts
+		JOIN tasks t ON t.id = ts.task_id
+		JOIN projects p ON p.id = t.project_id
+		LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id AND tsi.end_time IS NOT NULL
+		WHERE
+			p.client = ? COLLATE NOCASE AND
+			ts.billable = 1 AND
+			ts.started_at >= ? AND ts.started_at < ? AND
+			ts.id NOT IN (SELECT session_id FROM invoice_items)
+		GROUP BY ts.id
+		ORDER BY ts.started_at, ts.id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, client, formatTime(from), formatTime(to))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	inv := &Invoice{Client: client, From: from, To: to, Currency: currency, Lines: make([]*InvoiceLine, 0)}
+
+	var clients []string
+	for rows.Next() {
+		var (
+			line    InvoiceLine
+			owner   string
+			running bool
+			seconds int64
+		)
+		if err := rows.Scan(&line.SessionID, &line.WorkedOn, &line.Description, &line.Project, &owner, &running, &seconds); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		if running {
+			inv.Running++
+			continue
+		}
+		if seconds == 0 {
+			continue
+		}
+
+		line.Hours = math.Round(float64(seconds)/36) / 100
+		inv.Lines = append(inv.Lines, &line)
+		clients = append(clients, owner)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	// Bill the client by the name their projects use.
+	if len(clients) > 0 {
+		inv.Client = clients[0]
+	}
+
+	for i, line := range inv.Lines {
+		tags, err := SessionTagModel{m.DB}.GetBySession(line.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags: %w", err)
+		}
+
+		rate, ok := table.lookup(clients[i], line.Project, tags)
+		if !ok {
+			return nil, fmt.Errorf("%w to session #%d (%s)", ErrNoRate, line.SessionID, line.Project)
+		}
+
+		line.Rate = rate
+		line.Amount = math.Round(line.Hours*rate*100) / 100
+		inv.Hours += line.Hours
+		inv.Total += line.Amount
+	}
+
+	inv.Hours = math.Round(inv.Hours*100) / 100
+	inv.Total = math.Round(inv.Total*100) / 100
+
+	return inv, nil
+}
+
+// Record numbers the invoice and stores it with its lines. Numbers run per
+// year: prefix, year, dash and a four-digit sequence, e.g. INV-2025-0007.
+// It fails with ErrAlreadyInvoiced if another invoice took one of the
+// sessions in the meantime.
+func (m InvoiceModel) Record(inv *Invoice, prefix string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	year := fmt.Sprintf("%s%d-", prefix, time.Now().In(Location()).Year())
+
+	var issued int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM invoices WHERE number LIKE ? ESCAPE '\'`, escapeLike(year)+"%").Scan(&issued)
+	if err != nil {
+		return err
+	}
+	inv.Number = fmt.Sprintf("%s%04d", year, issued+1)
+
+	query := `
+		INSERT INTO invoices (number, client, period_start, period_end, currency, hours, total)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+	args := []any{inv.Number, inv.Client, formatTime(inv.From), formatTime(inv.To), inv.Currency, inv.Hours, inv.Total}
+	if err := tx.QueryRow(query, args...).Scan(&inv.ID, &inv.CreatedAt); err != nil {
+		return err
+	}
+
+	for _, line := range inv.Lines {
+		var taken bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM invoice_items WHERE session_id = ?)`, line.SessionID).Scan(&taken); err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: #%d", ErrAlreadyInvoiced, line.SessionID)
+		}
+
+		_, err := tx.Exec(`
+			INSERT INTO invoice_items (invoice_id, session_id, description, project, worked_on, hours, rate, amount)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`, inv.ID, line.SessionID, line.Description, line.Project, formatTime(line.WorkedOn), line.Hours, line.Rate, line.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// GetAll lists invoices newest first, without their lines.
+func (m InvoiceModel) GetAll() ([]*Invoice, error) {
+	query := `
+		SELECT id, number, client, period_start, period_end, currency, hours, total, created_at
+		FROM invoices
+		ORDER BY created_at DESC, id DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invoices := make([]*Invoice, 0)
+	for rows.Next() {
+		var inv Invoice
+		err := rows.Scan(&inv.ID, &inv.Number, &inv.Client, &inv.From, &inv.To, &inv.Currency, &inv.Hours, &inv.Total, &inv.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, &inv)
+	}
+
+	return invoices, rows.Err()
+}
+
+// GetByNumber loads a recorded invoice with its lines.
+func (m InvoiceModel) GetByNumber(number string) (*Invoice, error) {
+	query := `
+		SELECT id, number, client, period_start, period_end, currency, hours, total, created_at
+		FROM invoices
+		WHERE number = ?
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var inv Invoice
+	err := m.DB.QueryRowContext(ctx, query, number).Scan(
+		&inv.ID, &inv.Number, &inv.Client, &inv.From, &inv.To, &inv.Currency, &inv.Hours, &inv.Total, &inv.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := m.DB.QueryContext(ctx, `
+		SELECT session_id, worked_on, description, project, hours, rate, amount
+		FROM invoice_items
+		WHERE invoice_id = ?
+		ORDER BY worked_on, session_id
+	`, inv.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	inv.Lines = make([]*InvoiceLine, 0)
+	for rows.Next() {
+		var line InvoiceLine
+		if err := rows.Scan(&line.SessionID, &line.WorkedOn, &line.Description, &line.Project, &line.Hours, &line.Rate, &line.Amount); err != nil {
+			return nil, err
+		}
+		inv.Lines = append(inv.Lines, &line)
+	}
+
+	return &inv, rows.Err()
+}
+
+// invoiceOfTX returns the number of the invoice a session is on, or "".
+func invoiceOfTX(tx *sql.Tx, sessionID int) (string, error) {
+	var number string
+	err := tx.QueryRow(`
+		SELECT i.number
+		FROM invoice_items ii
+		JOIN invoices i ON i.id = ii.invoice_id
+		WHERE ii.session_id = ?
+	`, sessionID).Scan(&number)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return number, err
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,252 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// seedProject inserts a project for client and returns its ID.
+func seedProject(t *testing.T, db *sql.DB, name, client string) int {
+	t.Helper()
+
+	var id int
+	if err := db.QueryRow(`INSERT INTO projects (name, client) VALUES (?, ?) RETURNING id`, name, client).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// seedProjectTask inserts a task filed under a project and returns its ID.
+func seedProjectTask(t *testing.T, db *sql.DB, description string, projectID int) int {
+	t.Helper()
+
+	id := seedTask(t, db, description)
+	if _, err := db.Exec(`UPDATE tasks SET project_id = ? WHERE id = ?`, projectID, id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func TestRateLookup(t *testing.T) {
+	table := newRateTable([]*Rate{
+		{Scope: RateDefault, Hourly: 50},
+		{Scope: RateClient, Name: "Acme", Hourly: 80},
+		{Scope: RateProject, Name: "api", Hourly: 100},
+		{Scope: RateTag, Name: "urgent", Hourly: 150},
+		{Scope: RateTag, Name: "rush", Hourly: 120},
+	})
+
+	tests := []struct {
+		name    string
+		client  string
+		project string
+		tags    []string
+		want    float64
+	}{
+		{name: "highest tag", client: "Acme", project: "api", tags: []string{"rush", "urgent"}, want: 150},
+		{name: "tag over project", client: "Acme", project: "api", tags: []string{"rush"}, want: 120},
+		{name: "project over client", client: "Acme", project: "api", tags: []string{"docs"}, want: 100},
+		{name: "client over default", client: "Acme", project: "web", want: 80},
+		{name: "default", client: "Beta", project: "web", want: 50},
+		{name: "any case", client: "ACME", project: "API", tags: []string{"Urgent"}, want: 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.lookup(tt.client, tt.project, tt.tags)
+			if !ok || got != tt.want {
+				t.Fatalf("got %v, %v, want %v", got, ok, tt.want)
+			}
+		})
+	}
+
+	if rate, ok := newRateTable(nil).lookup("Acme", "api", nil); ok {
+		t.Fatalf("without rates: got %v", rate)
+	}
+}
+
+func TestInvoiceDraft(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	api := seedProjectTask(t, db, "Build API", seedProject(t, db, "api", "Acme"))
+	web := seedProjectTask(t, db, "Build site", seedProject(t, db, "web", "Acme"))
+	other := seedProjectTask(t, db, "Other work", seedProject(t, db, "beta", "Beta"))
+
+	for _, r := range []Rate{
+		{Scope: RateClient, Name: "Acme", Hourly: 80},
+		{Scope: RateProject, Name: "api", Hourly: 100},
+		{Scope: RateTag, Name: "urgent", Hourly: 150},
+	} {
+		if err := m.Rates.Set(r.Scope, r.Name, r.Hourly); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	billed := seedSession(t, db, api, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+	short := seedSession(t, db, web, "a", span{"2025-03-04 09:00", "2025-03-04 09:30"})
+	tagged := seedSession(t, db, web, "a", span{"2025-03-05 09:00", "2025-03-05 09:45"}, span{"2025-03-05 10:00", "2025-03-05 10:15"})
+	if err := inTx(t, db, func(tx *sql.Tx) error {
+		return m.SessionTags.Create(tx, tagged.ID, []string{"urgent"})
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	nonBillable := seedSession(t, db, api, "a", span{"2025-03-06 09:00", "2025-03-06 10:00"})
+	if _, err := db.Exec(`UPDATE task_sessions SET billable = 0 WHERE id = ?`, nonBillable.ID); err != nil {
+		t.Fatal(err)
+	}
+	seedSession(t, db, other, "a", span{"2025-03-07 09:00", "2025-03-07 10:00"})
+	seedSession(t, db, api, "a", span{"2025-02-28 09:00", "2025-02-28 10:00"})
+	seedSession(t, db, api, "a", span{"2025-03-10 09:00", ""})
+
+	inv, err := m.Invoices.Draft("acme", at(t, "2025-03-01 00:00"), at(t, "2025-04-01 00:00"), "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type line struct {
+		session             int
+		hours, rate, amount float64
+	}
+	var got []line
+	for _, l := range inv.Lines {
+		got = append(got, line{l.SessionID, l.Hours, l.Rate, l.Amount})
+	}
+	want := []line{
+		{billed.ID, 1, 100, 100},
+		{short.ID, 0.5, 80, 40},
+		{tagged.ID, 1, 150, 150},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("lines: got %+v, want %+v", got, want)
+	}
+
+	if inv.Client != "Acme" || inv.Hours != 2.5 || inv.Total != 290 || inv.Running != 1 {
+		t.Errorf("invoice: got client %q, %v hours, total %v, %d running", inv.Client, inv.Hours, inv.Total, inv.Running)
+	}
+}
+
+func TestInvoiceDraftWithoutRate(t *testing.T) {
+	db := newTestDB(t)
+
+	task := seedProjectTask(t, db, "Build API", seedProject(t, db, "api", "Acme"))
+	seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+
+	_, err := NewModels(db).Invoices.Draft("Acme", at(t, "2025-03-01 00:00"), at(t, "2025-04-01 00:00"), "USD")
+	if !errors.Is(err, ErrNoRate) {
+		t.Fatalf("got %v, want %v", err, ErrNoRate)
+	}
+}
+
+func TestInvoiceRecord(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	if err := m.Rates.Set(RateDefault, "", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	task := seedProjectTask(t, db, "Build API", seedProject(t, db, "api", "Acme"))
+	march := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+	seedSession(t, db, task, "a", span{"2025-04-03 09:00", "2025-04-03 10:00"})
+	seedSession(t, db, task, "a", span{"2025-05-05 09:00", "2025-05-05 10:00"})
+
+	// Numbers run per prefix and year: last year's and another prefix's
+	// invoices don't count.
+	year := time.Now().In(Location()).Year()
+	invoice(t, db, fmt.Sprintf("INV-%d-0009", year-1))
+	invoice(t, db, fmt.Sprintf("ACME-%d-0001", year))
+
+	month := func(from string) *Invoice {
+		t.Helper()
+
+		start := at(t, from)
+		inv, err := m.Invoices.Draft("Acme", start, start.AddDate(0, 1, 0), "USD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return inv
+	}
+
+	tests := []struct {
+		from   string
+		prefix string
+		want   string
+	}{
+		{from: "2025-03-01 00:00", prefix: "INV-", want: fmt.Sprintf("INV-%d-0001", year)},
+		{from: "2025-04-01 00:00", prefix: "INV-", want: fmt.Sprintf("INV-%d-0002", year)},
+		{from: "2025-05-01 00:00", prefix: "ACME-", want: fmt.Sprintf("ACME-%d-0002", year)},
+	}
+	for _, tt := range tests {
+		inv := month(tt.from)
+		if err := m.Invoices.Record(inv, tt.prefix); err != nil {
+			t.Fatal(err)
+		}
+		if inv.Number != tt.want {
+			t.Errorf("got number %s, want %s", inv.Number, tt.want)
+		}
+	}
+
+	recorded, err := m.Invoices.GetByNumber(fmt.Sprintf("INV-%d-0001", year))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recorded.Lines) != 1 || recorded.Lines[0].SessionID != march.ID || recorded.Lines[0].Amount != 100 {
+		t.Errorf("recorded lines: got %+v", recorded.Lines)
+	}
+
+	if again := month("2025-03-01 00:00"); len(again.Lines) != 0 {
+		t.Errorf("invoiced sessions are drafted again: %+v", again.Lines)
+	}
+}
+
+func TestInvoiceRecordTwice(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	if err := m.Rates.Set(RateDefault, "", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	task := seedProjectTask(t, db, "Build API", seedProject(t, db, "api", "Acme"))
+	ts := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+
+	// Two drafts of the same hours, as from two terminals.
+	var drafts []*Invoice
+	for range 2 {
+		inv, err := m.Invoices.Draft("Acme", at(t, "2025-03-01 00:00"), at(t, "2025-04-01 00:00"), "USD")
+		if err != nil {
+			t.Fatal(err)
+		}
+		drafts = append(drafts, inv)
+	}
+
+	if err := m.Invoices.Record(drafts[0], "INV-"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Invoices.Record(drafts[1], "INV-"); !errors.Is(err, ErrAlreadyInvoiced) {
+		t.Fatalf("got %v, want %v", err, ErrAlreadyInvoiced)
+	}
+
+	invoices, err := m.Invoices.GetAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(invoices) != 1 {
+		t.Fatalf("got %d invoices, want the refused one rolled back", len(invoices))
+	}
+
+	// The schema refuses a second line for the session too.
+	_, err = db.Exec(`
+		INSERT INTO invoice_items (invoice_id, session_id, description, worked_on, hours, rate, amount)
+		VALUES (?, ?, 'Again', '2025-03-03 00:00:00', 1, 100, 100)
+	`, invoices[0].ID, ts.ID)
+	if err == nil {
+		t.Fatal("a session was put on two invoice lines")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -21,15 +22,38 @@ type Commit struct {
 	Message   string
 	Author    string
 	Date      string
+	DiffStat
+}
+
+// DiffStat is the size of a commit's change: files touched and lines added
+// and removed. Binary files count as changed files without lines.
+type DiffStat struct {
+	Files      int `json:"files_changed"`
+	Insertions int `json:"insertions"`
+	Deletions  int `json:"deletions"`
+}
+
+// Add sums two diff stats.
+func (d DiffStat) Add(other DiffStat) DiffStat {
+	return DiffStat{d.Files + other.Files, d.Insertions + other.Insertions, d.Deletions + other.Deletions}
+}
+
+// String is the git --shortstat style summary, e.g. "3 files, +40 -12".
+func (d DiffStat) String() string {
+	files := "files"
+	if d.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s, +%d -%d", d.Files, files, d.Insertions, d.Deletions)
 }
 
 // Create inserts a commit into the DB.
 func (m CommitModel) Create(c *Commit) error {
 	query := `
-		INSERT OR IGNORE INTO commits (hash, session_id, message, author, date)
-		VALUES (?, ?, ?, ?, ?)
+		INSERT OR IGNORE INTO commits (hash, session_id, message, author, date, files_changed, insertions, deletions)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	args := []any{c.Hash, c.SessionID, c.Message, c.Author, c.Date}
+	args := []any{c.Hash, c.SessionID, c.Message, c.Author, c.Date, c.Files, c.Insertions, c.Deletions}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -41,13 +65,21 @@ func (m CommitModel) Create(c *Commit) error {
 
 // GetAllHashes returns a map of all commit hashes stored in the DB.
 func (m CommitModel) GetAllHashes() (map[string]bool, error) {
+	return m.hashes("SELECT hash FROM commits")
+}
+
+// hashesWithoutStats returns the hashes of recorded commits whose diff stats
+// are still zero, such as those stored before stats were read.
+func (m CommitModel) hashesWithoutStats() (map[string]bool, error) {
+	return m.hashes("SELECT hash FROM commits WHERE files_changed = 0")
+}
+
+func (m CommitModel) hashes(query string) (map[string]bool, error) {
 	existing := make(map[string]bool)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	query := "SELECT hash FROM commits"
-
 	rows, err := m.DB.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query commits: %w", err)
@@ -65,20 +97,22 @@ func (m CommitModel) GetAllHashes() (map[string]bool, error) {
 	return existing, nil
 }
 
-// FetchGitCommits runs git log and returns a slice of Commit structs.
+// FetchGitCommits runs git log and returns a slice of Commit structs, with
+// the diff stats of each.
 func FetchGitCommits(sessionID *int) ([]*Commit, error) {
-	cmd := exec.Command("git", "log", "--pretty=format:%H|%an|%ad|%s")
+	// Records start with \x1e and fields are split by \x1f, so neither can
+	// clash with author names or subjects. The numstat lines follow each
+	// record's header line.
+	cmd := exec.Command("git", "log", "--numstat", "--pretty=format:%x1e%H%x1f%an%x1f%ad%x1f%s")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, fmt.Errorf("failed to run git log: %w", err)
 	}
 
 	var commits []*Commit
-	scanner := bufio.NewScanner(strings.NewReader(string(output)))
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.SplitN(line, "|", 4)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		header, numstat, _ := strings.Cut(record, "\n")
+		parts := strings.SplitN(header, "\x1f", 4)
 		if len(parts) < 4 {
 			continue
 		}
@@ -88,34 +122,82 @@ func FetchGitCommits(sessionID *int) ([]*Commit, error) {
 			Author:    parts[1],
 			Date:      parts[2],
 			Message:   parts[3],
+			DiffStat:  parseNumstat(numstat),
 		}
 		commits = append(commits, commit)
 	}
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("scanner error: %w", err)
-	}
 
 	return commits, nil
 }
 
+// GitCommitStats returns the diff stats of a commit in the repository
+// containing dir.
+func GitCommitStats(dir, hash string) (DiffStat, error) {
+	cmd := exec.Command("git", "show", "--numstat", "--format=", hash)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return DiffStat{}, fmt.Errorf("failed to run git show: %w", err)
+	}
+
+	return parseNumstat(string(output)), nil
+}
+
+// parseNumstat totals git --numstat output: one "added<TAB>deleted<TAB>path"
+// line per file, with "-" for the counts of binary files.
+func parseNumstat(output string) DiffStat {
+	var stat DiffStat
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		stat.Files++
+		if n, err := strconv.Atoi(fields[0]); err == nil {
+			stat.Insertions += n
+		}
+		if n, err := strconv.Atoi(fields[1]); err == nil {
+			stat.Deletions += n
+		}
+	}
+
+	return stat
+}
+
 func (m CommitModel) SyncCommits(sessionID *int) (int, error) {
 	existing, err := m.GetAllHashes()
 	if err != nil {
 		return 0, err
 	}
 
+	missingStats, err := m.hashesWithoutStats()
+	if err != nil {
+		return 0, err
+	}
+
 	allCommits, err := FetchGitCommits(sessionID)
 	if err != nil {
 		return 0, err
 	}
 
-	var newCommits []*Commit
+	var newCommits, statCommits []*Commit
 	for _, c := range allCommits {
-		if !existing[c.Hash] {
+		switch {
+		case !existing[c.Hash]:
 			newCommits = append(newCommits, c)
+		case missingStats[c.Hash] && c.Files > 0:
+			statCommits = append(statCommits, c)
 		}
 	}
 
+	if err := m.fillStats(statCommits); err != nil {
+		fmt.Printf("Failed to update the stats of recorded commits: %v\n", err)
+	}
+
 	for _, c := range newCommits {
 		if err := m.Create(c); err != nil {
 			// Just log and continue to avoid failing the whole sync
@@ -126,3 +208,97 @@ func (m CommitModel) SyncCommits(sessionID *int) (int, error) {
 
 	return len(newCommits), nil
 }
+
+// fillStats sets the diff stats of commits recorded without them, in one
+// transaction.
+func (m CommitModel) fillStats(commits []*Commit) error {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE commits
+		SET files_changed = ?, insertions = ?, deletions = ?
+		WHERE hash = ? AND files_changed = 0
+	`
+	for _, c := range commits {
+		if _, err := tx.Exec(query, c.Files, c.Insertions, c.Deletions, c.Hash); err != nil {
+			return fmt.Errorf("commit %s: %w", c.Hash, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// commitDateLayouts are the formats a commit date may be stored in: git's
+// default log format, git's ISO formats and plain RFC 3339.
+var commitDateLayouts = []string{
+	"Mon Jan 2 15:04:05 2006 -0700",
+	"2006-01-02 15:04:05 -0700",
+	time.RFC3339,
+	timeLayout,
+}
+
+// ParseCommitDate parses a stored commit date.
+func ParseCommitDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range commitDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised commit date %q", value)
+}
+
+// GetBySession returns the commits attached to a session.
+func (m CommitModel) GetBySession(sessionID int) ([]*Commit, error) {
+	query := `
+		SELECT
+			id, session_id, hash, COALESCE(message, ''), COALESCE(author, ''), COALESCE(date, ''),
+			files_changed, insertions, deletions
+		FROM commits
+		WHERE session_id = ?
+		ORDER BY id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commits: %w", err)
+	}
+	defer rows.Close()
+
+	var commits []*Commit
+	for rows.Next() {
+		var c Commit
+		if err := rows.Scan(&c.ID, &c.SessionID, &c.Hash, &c.Message, &c.Author, &c.Date, &c.Files, &c.Insertions, &c.Deletions); err != nil {
+			return nil, fmt.Errorf("failed to scan commit: %w", err)
+		}
+		commits = append(commits, &c)
+	}
+
+	return commits, rows.Err()
+}
+
+// ReassignTX attaches a single commit to another session.
+func (m CommitModel) ReassignTX(tx *sql.Tx, commitID, sessionID int) error {
+	_, err := tx.Exec(`UPDATE commits SET session_id = ? WHERE id = ?`, sessionID, commitID)
+	return err
+}
+
+// MoveTX attaches every commit of one session to another.
+func (m CommitModel) MoveTX(tx *sql.Tx, fromSessionID, toSessionID int) error {
+	_, err := tx.Exec(`UPDATE commits SET session_id = ? WHERE session_id = ?`, toSessionID, fromSessionID)
+	return err
+}
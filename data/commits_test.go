@@ -0,0 +1,76 @@
+package data
+
+import "testing"
+
+func TestParseNumstat(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   DiffStat
+	}{
+		{name: "empty", output: "", want: DiffStat{}},
+		{name: "one file", output: "3\t1\tmain.go\n", want: DiffStat{Files: 1, Insertions: 3, Deletions: 1}},
+		{
+			name:   "several files",
+			output: "10\t2\tdata/db.go\n0\t7\tcmd/root.go\n\n1\t1\tREADME.md\n",
+			want:   DiffStat{Files: 3, Insertions: 11, Deletions: 10},
+		},
+		{name: "binary file", output: "-\t-\tlogo.png\n4\t0\tweb/index.html\n", want: DiffStat{Files: 2, Insertions: 4}},
+		{name: "path with tab", output: "2\t0\tdocs/a\tb.md\n", want: DiffStat{Files: 1, Insertions: 2}},
+		{name: "not numstat", output: "commit abc123\nAuthor: Jane\n", want: DiffStat{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNumstat(tt.output); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFillStats(t *testing.T) {
+	db := newTestDB(t)
+	m := CommitModel{db}
+
+	for _, c := range []*Commit{
+		{Hash: "old"},
+		{Hash: "counted", DiffStat: DiffStat{Files: 1, Insertions: 1}},
+	} {
+		if err := m.Create(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	missing, err := m.hashesWithoutStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !missing["old"] || missing["counted"] {
+		t.Fatalf("commits without stats: got %v", missing)
+	}
+
+	err = m.fillStats([]*Commit{
+		{Hash: "old", DiffStat: DiffStat{Files: 2, Insertions: 5, Deletions: 3}},
+		{Hash: "counted", DiffStat: DiffStat{Files: 9, Insertions: 9, Deletions: 9}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]DiffStat{
+		"old":     {Files: 2, Insertions: 5, Deletions: 3},
+		"counted": {Files: 1, Insertions: 1},
+	}
+	for hash, stat := range want {
+		var got DiffStat
+		err := db.QueryRow(`SELECT files_changed, insertions, deletions FROM commits WHERE hash = ?`, hash).
+			Scan(&got.Files, &got.Insertions, &got.Deletions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != stat {
+			t.Errorf("%s: got %+v, want %+v", hash, got, stat)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package data
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestDB opens a fresh database in a temporary directory with every
+// migration applied.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db := NewSQLiteDB(filepath.Join(t.TempDir(), "db.sqlite"))
+	t.Cleanup(func() { db.Close() })
+
+	migrate(t, db, "")
+	return db
+}
+
+// migrate applies the up migrations in order, stopping before the one whose
+// file name starts with before. An empty before applies them all.
+func migrate(t *testing.T, db *sql.DB, before string) {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join("..", "migrations", "*.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		if before != "" && strings.HasPrefix(filepath.Base(file), before) {
+			return
+		}
+
+		migration, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(string(migration)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+// at parses a "2006-01-02 15:04" time in UTC.
+func at(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse("2006-01-02 15:04", value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// span is an interval as "2006-01-02 15:04" times; an empty end leaves it
+// running.
+type span struct {
+	start, end string
+}
+
+// seedTask inserts a task and returns its ID.
+func seedTask(t *testing.T, db *sql.DB, description string) int {
+	t.Helper()
+
+	var id int
+	if err := db.QueryRow(`INSERT INTO tasks (description) VALUES (?) RETURNING id`, description).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// seedSession inserts a session on task in workspace with the given
+// intervals. It spans them, and is left running when the last one is.
+func seedSession(t *testing.T, db *sql.DB, taskID int, workspace string, spans ...span) *TaskSession {
+	t.Helper()
+
+	var ended any
+	if end := spans[len(spans)-1].end; end != "" {
+		ended = formatTime(at(t, end))
+	}
+
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO task_sessions (task_id, started_at, ended_at, mode, workspace)
+		VALUES (?, ?, ?, 'personal', ?)
+		RETURNING id
+	`, taskID, formatTime(at(t, spans[0].start)), ended, workspace).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range spans {
+		var end any
+		if s.end != "" {
+			end = formatTime(at(t, s.end))
+		}
+		_, err := db.Exec(`INSERT INTO task_session_intervals (session_id, start_time, end_time) VALUES (?, ?, ?)`,
+			id, formatTime(at(t, s.start)), end)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ts, err := TaskSessionModel{db}.GetByID(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// inTx runs fn in a transaction and commits it when fn succeeds.
+func inTx(t *testing.T, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	t.Helper()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// intervalBounds lists a session's intervals as "15:04-15:04", with "…" for
+// a running end.
+func intervalBounds(t *testing.T, db *sql.DB, sessionID int) []string {
+	t.Helper()
+
+	intervals, err := TaskSessionIntervalModel{db}.GetAllForSession(sessionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bounds := make([]string, 0, len(intervals))
+	for _, tsi := range intervals {
+		end := "…"
+		if tsi.EndTime != nil {
+			end = tsi.EndTime.UTC().Format("15:04")
+		}
+		bounds = append(bounds, tsi.StartTime.UTC().Format("15:04")+"-"+end)
+	}
+	return bounds
+}
+
+// invoice puts sessions on a recorded invoice.
+func invoice(t *testing.T, db *sql.DB, number string, sessionIDs ...int) {
+	t.Helper()
+
+	var id int
+	err := db.QueryRow(`
+		INSERT INTO invoices (number, client, period_start, period_end, currency, hours, total)
+		VALUES (?, 'Acme', '2025-03-01 00:00:00', '2025-04-01 00:00:00', 'USD', 1, 100)
+		RETURNING id
+	`, number).Scan(&id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, sessionID := range sessionIDs {
+		_, err := db.Exec(`
+			INSERT INTO invoice_items (invoice_id, session_id, description, worked_on, hours, rate, amount)
+			VALUES (?, ?, 'Work', '2025-03-03 00:00:00', 1, 100, 100)
+		`, id, sessionID)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// EstimateStat compares the time estimated for a task, or for all estimated
+// tasks with a tag, against the active time logged on them.
+type EstimateStat struct {
+	TaskID          int     `json:"task_id,omitempty"`
+	Name            string  `json:"name"`
+	Tasks           int     `json:"tasks"`
+	EstimateSeconds int64   `json:"estimate_seconds"`
+	ActualSeconds   int64   `json:"actual_seconds"`
+	Ratio           float64 `json:"ratio"`
+}
+
+type EstimateReport struct {
+	Tasks []*EstimateStat `json:"tasks"`
+	Tags  []*EstimateStat `json:"tags"`
+}
+
+func (s *EstimateStat) Estimate() time.Duration {
+	return time.Duration(s.EstimateSeconds) * time.Second
+}
+
+func (s *EstimateStat) Actual() time.Duration {
+	return time.Duration(s.ActualSeconds) * time.Second
+}
+
+// estimatedTasksQuery lists every task with an estimate and the active time
+// across all of its sessions. Running intervals count up to now.
+const estimatedTasksQuery = `
+	SELECT
+		t.id,
+		t.description,
+		t.estimate_seconds,
+		COALESCE(SUM(
+			strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+		), 0) AS actual_seconds
+	FROM tasks t
+	LEFT JOIN task_sessions ts ON ts.task_id = t.id
+	LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+	WHERE t.estimate_seconds IS NOT NULL
+	GROUP BY t.id
+`
+
+// ActiveTime returns the active time logged on a task across all of its
+// sessions, including a running interval.
+func (m TaskModel) ActiveTime(taskID int) (time.Duration, error) {
+	query := `
+		SELECT COALESCE(SUM(
+			strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+		), 0)
+		FROM task_sessions ts
+		JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+		WHERE ts.task_id = ?
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var seconds int64
+	if err := m.DB.QueryRowContext(ctx, query, taskID).Scan(&seconds); err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// GetEstimateReport compares estimates with actual time for every estimated
+// task, newest first, and for every tag used on an estimated task. A tag
+// totals the estimates and time of the tasks it was used on.
+func GetEstimateReport(db *sql.DB) (*EstimateReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	report := &EstimateReport{
+		Tasks: make([]*EstimateStat, 0),
+		Tags:  make([]*EstimateStat, 0),
+	}
+
+	rows, err := db.QueryContext(ctx, estimatedTasksQuery+` ORDER BY t.created_at DESC, t.id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stat := EstimateStat{Tasks: 1}
+		if err := rows.Scan(&stat.TaskID, &stat.Name, &stat.EstimateSeconds, &stat.ActualSeconds); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stat.Ratio = estimateRatio(stat.EstimateSeconds, stat.ActualSeconds)
+		report.Tasks = append(report.Tasks, &stat)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	tagQuery := `
+		WITH estimated AS (` + estimatedTasksQuery + `),
+		tagged AS (
+			SELECT DISTINCT st.tag, ts.task_id
+			FROM session_tags st
+			JOIN task_sessions ts ON ts.id = st.session_id
+		)
+		SELECT
+			tg.tag,
+			COUNT(*),
+			SUM(e.estimate_seconds),
+			SUM(e.actual_seconds)
+		FROM tagged tg
+		JOIN estimated e ON e.id = tg.task_id
+		GROUP BY tg.tag
+		ORDER BY tg.tag
+	`
+
+	tagRows, err := db.QueryContext(ctx, tagQuery)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer tagRows.Close()
+
+	for tagRows.Next() {
+		var stat EstimateStat
+		if err := tagRows.Scan(&stat.Name, &stat.Tasks, &stat.EstimateSeconds, &stat.ActualSeconds); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stat.Ratio = estimateRatio(stat.EstimateSeconds, stat.ActualSeconds)
+		report.Tags = append(report.Tags, &stat)
+	}
+
+	if err := tagRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	return report, nil
+}
+
+// estimateRatio is actual time over estimated time, rounded to two places:
+// 1 is spot on, above 1 is over budget.
+func estimateRatio(estimate, actual int64) float64 {
+	if estimate <= 0 {
+		return 0
+	}
+	return math.Round(float64(actual)/float64(estimate)*100) / 100
+}
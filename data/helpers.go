@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,38 +27,79 @@ type SummaryStats struct {
 	WeekHours         MetricStat `json:"week_hours"`
 	SessionsToday     MetricStat `json:"sessions_today"`
 	ProductivityScore MetricStat `json:"productivity_score"`
+	WeekOvertime      MetricStat `json:"week_overtime"`
+
+	// Rounding describes how the hours were rounded, "none" if exact.
+	Rounding string `json:"rounding"`
 }
 
 type DailyStat struct {
 	Date     string  `json:"date"`
 	Hours    float64 `json:"hours"`
+	Overtime float64 `json:"overtime"`
 	Sessions int     `json:"sessions"`
 }
 
 type WeeklyStat struct {
 	Start    string  `json:"week_start"`
 	Hours    float64 `json:"hours"`
+	Overtime float64 `json:"overtime"`
 	Sessions int     `json:"sessions"`
 }
 
 type MonthlyStat struct {
 	Month    string  `json:"month"`
 	Hours    float64 `json:"hours"`
+	Overtime float64 `json:"overtime"`
 	Sessions int     `json:"sessions"`
 }
 
 type Session struct {
-	ID        int     `json:"id"`
-	Task      string  `json:"task"`
-	StartTime string  `json:"start_time"`
-	EndTime   *string `json:"end_time"`
-	Duration  string  `json:"duration"`
-	Status    string  `json:"status"`
+	ID         int     `json:"id"`
+	Task       string  `json:"task"`
+	Project    string  `json:"project"`
+	Parent     string  `json:"parent"`
+	TaskStatus string  `json:"task_status"`
+	StartTime  string  `json:"start_time"`
+	EndTime    *string `json:"end_time"`
+	Duration   string  `json:"duration"`
+	Status     string  `json:"status"`
+	// DiffStat is the churn of the session's commits.
+	DiffStat
+}
+
+// SessionDetail is a session with its journal notes and commits.
+type SessionDetail struct {
+	*Session
+	Notes   []*SessionNote   `json:"notes"`
+	Commits []*SessionCommit `json:"commits"`
 }
 
+type SessionCommit struct {
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	DiffStat
+}
+
+// CreateTask starts a session for task, inserting the task first unless it
+// already has an ID.
 func (m Models) CreateTask(tx *sql.Tx, task *Task, ts *TaskSession) error {
-	if err := m.Tasks.CreateTx(tx, task); err != nil {
-		return err
+	if !ts.StartedAt.IsZero() {
+		overlaps, err := m.TaskSessionIntervals.OverlapsTX(tx, ts.Workspace, 0, ts.StartedAt, time.Now())
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return ErrOverlappingInterval
+		}
+	}
+
+	if task.ID == 0 {
+		if err := m.Tasks.CreateTx(tx, task); err != nil {
+			return err
+		}
 	}
 
 	ts.TaskID = task.ID
@@ -63,17 +108,503 @@ func (m Models) CreateTask(tx *sql.Tx, task *Task, ts *TaskSession) error {
 		return err
 	}
 
-	if err := m.TaskSessionIntervals.CreateTX(tx, session.ID); err != nil {
+	if err := m.TaskSessionIntervals.CreateTX(tx, session.ID, session.StartedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SwitchTask stops the current session, abandoning its running pomodoro, and
+// starts next on task, using the same timestamp for both so no time is lost
+// or counted twice.
+func (m Models) SwitchTask(tx *sql.Tx, currentID int, task *Task, next *TaskSession, at time.Time) (*TaskSession, error) {
+	if _, err := m.TaskSessionIntervals.EndTX(tx, currentID, at); err != nil {
+		return nil, fmt.Errorf("failed to close running interval: %w", err)
+	}
+
+	stopped, err := m.TaskSessions.StopTX(tx, currentID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop session: %w", err)
+	}
+	if stopped == nil {
+		return nil, fmt.Errorf("session #%d is no longer active", currentID)
+	}
+
+	if err := m.Pomodoros.AbandonRunningTX(tx, currentID, at); err != nil {
+		return nil, fmt.Errorf("failed to abandon running pomodoro: %w", err)
+	}
+
+	next.StartedAt = at
+	if err := m.CreateTask(tx, task, next); err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	return stopped, nil
+}
+
+// CreateRetroactiveTask records a task together with a session that has
+// already ended. The intervals must be closed and may not overlap each other
+// or any interval already in the database.
+func (m Models) CreateRetroactiveTask(tx *sql.Tx, task *Task, ts *TaskSession, intervals []*TaskSessionInterval) error {
+	if ts.EndedAt == nil || !ts.EndedAt.After(ts.StartedAt) {
+		return ErrInvalidInterval
+	}
+
+	if len(intervals) == 0 {
+		return errors.New("a session needs at least one interval")
+	}
+
+	if err := ValidateIntervals(ts.StartedAt, *ts.EndedAt, intervals); err != nil {
+		return err
+	}
+
+	for _, tsi := range intervals {
+		overlaps, err := m.TaskSessionIntervals.OverlapsTX(tx, ts.Workspace, 0, tsi.StartTime, *tsi.EndTime)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return ErrOverlappingInterval
+		}
+	}
+
+	if err := m.Tasks.CreateTx(tx, task); err != nil {
+		return err
+	}
+
+	ts.TaskID = task.ID
+	if _, err := m.TaskSessions.CreateClosedTX(tx, ts); err != nil {
+		return err
+	}
+
+	for _, tsi := range intervals {
+		tsi.SessionID = ts.ID
+		if err := m.TaskSessionIntervals.CreateClosedTX(tx, tsi); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpdateSession writes edited session bounds and intervals in one go;
+// intervals without an ID are added. Only an active session may keep an open
+// interval, and it has to be the last one.
+// The intervals are checked against each other, the session bounds and the
+// intervals of every other session.
+func (m Models) UpdateSession(tx *sql.Tx, ts *TaskSession, intervals []*TaskSessionInterval) error {
+	now := time.Now()
+
+	sessionEnd := now
+	if ts.EndedAt != nil {
+		sessionEnd = *ts.EndedAt
+	}
+
+	if !sessionEnd.After(ts.StartedAt) {
+		return errors.New("session must end after it starts")
+	}
+
+	// An invoice bills the session's task and times as they were; only its
+	// other details can change.
+	if number, err := invoiceOfTX(tx, ts.ID); err != nil {
+		return err
+	} else if number != "" {
+		changed, err := billedTimesChangedTX(tx, ts, intervals)
+		if err != nil {
+			return err
+		}
+		if changed {
+			return fmt.Errorf("session #%d is on invoice %s; its task and times can't be changed", ts.ID, number)
+		}
+	}
+
+	// Validate against a copy where the open interval runs until now.
+	checked := make([]*TaskSessionInterval, len(intervals))
+	for i, tsi := range intervals {
+		c := *tsi
+		if c.EndTime == nil {
+			if ts.EndedAt != nil {
+				return fmt.Errorf("interval #%d is still open but the session has ended", tsi.ID)
+			}
+			c.EndTime = &sessionEnd
+		}
+		checked[i] = &c
+	}
+
+	if err := ValidateIntervals(ts.StartedAt, sessionEnd, checked); err != nil {
+		return err
+	}
+
+	for i, tsi := range checked {
+		if tsi.EndTime == &sessionEnd && i != len(checked)-1 {
+			return fmt.Errorf("only the latest interval can be open")
+		}
+
+		overlaps, err := m.TaskSessionIntervals.OverlapsTX(tx, ts.Workspace, ts.ID, tsi.StartTime, *tsi.EndTime)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return ErrOverlappingInterval
+		}
+	}
+
+	if err := m.TaskSessions.UpdateTX(tx, ts); err != nil {
 		return err
 	}
 
+	for _, tsi := range intervals {
+		var err error
+		switch {
+		case tsi.ID != 0:
+			err = m.TaskSessionIntervals.UpdateTX(tx, tsi)
+		case tsi.EndTime == nil:
+			err = m.TaskSessionIntervals.CreateTX(tx, ts.ID, tsi.StartTime)
+		default:
+			tsi.SessionID = ts.ID
+			err = m.TaskSessionIntervals.CreateClosedTX(tx, tsi)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// billedTimesChangedTX reports whether ts or its intervals differ from what
+// is stored in the task, start, end or interval bounds.
+func billedTimesChangedTX(tx *sql.Tx, ts *TaskSession, intervals []*TaskSessionInterval) (bool, error) {
+	var (
+		taskID             int
+		startedAt, endedAt sql.NullString
+	)
+	err := tx.QueryRow(`SELECT task_id, started_at, ended_at FROM task_sessions WHERE id = ?`, ts.ID).Scan(&taskID, &startedAt, &endedAt)
+	if err != nil {
+		return false, err
+	}
+	if taskID != ts.TaskID {
+		return true, nil
+	}
+	for _, b := range []struct {
+		stored sql.NullString
+		t      *time.Time
+	}{{startedAt, &ts.StartedAt}, {endedAt, ts.EndedAt}} {
+		if same, err := storedTimeIs(b.stored, b.t); err != nil || !same {
+			return !same, err
+		}
+	}
+
+	rows, err := tx.Query(`SELECT id, start_time, end_time FROM task_session_intervals WHERE session_id = ?`, ts.ID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int]*TaskSessionInterval, len(intervals))
+	for _, tsi := range intervals {
+		byID[tsi.ID] = tsi
+	}
+
+	stored := 0
+	for rows.Next() {
+		var (
+			id         int
+			start, end sql.NullString
+		)
+		if err := rows.Scan(&id, &start, &end); err != nil {
+			return false, err
+		}
+		stored++
+
+		tsi, ok := byID[id]
+		if !ok {
+			return true, nil
+		}
+		if same, err := storedTimeIs(start, &tsi.StartTime); err != nil || !same {
+			return !same, err
+		}
+		if same, err := storedTimeIs(end, tsi.EndTime); err != nil || !same {
+			return !same, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	return stored != len(intervals), nil
+}
+
+// storedTimeIs reports whether a stored, possibly NULL, timestamp is t.
+func storedTimeIs(stored sql.NullString, t *time.Time) (bool, error) {
+	if !stored.Valid || t == nil {
+		return !stored.Valid && t == nil, nil
+	}
+	parsed, err := parseStoredTime(stored.String)
+	if err != nil {
+		return false, err
+	}
+	return parsed.Equal(*t), nil
+}
+
+// SplitSession cuts ts in two at the given time. Everything from at onwards
+// (the rest of the interval running across the cut, later intervals,
+// pomodoros, events and commits) moves to a new session on task, which
+// inherits the mode, tags and KPIs. Task is inserted first unless it already
+// has an ID. It returns the new session and the number of commits moved.
+func (m Models) SplitSession(tx *sql.Tx, ts *TaskSession, task *Task, at time.Time) (*TaskSession, int, error) {
+	end := time.Now()
+	if ts.EndedAt != nil {
+		end = *ts.EndedAt
+	}
+
+	if !at.After(ts.StartedAt) || !at.Before(end) {
+		return nil, 0, errors.New("split time must fall inside the session")
+	}
+
+	if number, err := invoiceOfTX(tx, ts.ID); err != nil {
+		return nil, 0, err
+	} else if number != "" {
+		return nil, 0, fmt.Errorf("session #%d is on invoice %s and can't be split", ts.ID, number)
+	}
+
+	intervals, err := m.TaskSessionIntervals.GetAllForSession(ts.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	commits, err := m.Commits.GetBySession(ts.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tags, err := m.SessionTags.GetBySession(ts.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	kpis, err := m.SessionKPI.GetBySession(ts.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if task.ID == 0 {
+		if err := m.Tasks.CreateTx(tx, task); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	next := &TaskSession{
+		TaskID:    task.ID,
+		StartedAt: at,
+		EndedAt:   ts.EndedAt,
+		Mode:      ts.Mode,
+		Synced:    false,
+		Workspace: ts.Workspace,
+	}
+
+	if next.EndedAt != nil {
+		_, err = m.TaskSessions.CreateClosedTX(tx, next)
+	} else {
+		_, err = m.TaskSessions.CreateTX(tx, next)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if !ts.Billable {
+		if err := m.TaskSessions.SetBillableTX(tx, next.ID, false); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err := m.TaskSessionIntervals.MoveTX(tx, ts.ID, next.ID, at); err != nil {
+		return nil, 0, err
+	}
+
+	for _, tsi := range intervals {
+		crosses := tsi.StartTime.Before(at) && (tsi.EndTime == nil || tsi.EndTime.After(at))
+		if !crosses {
+			continue
+		}
+
+		if tsi.EndTime == nil {
+			err = m.TaskSessionIntervals.CreateTX(tx, next.ID, at)
+		} else {
+			err = m.TaskSessionIntervals.CreateClosedTX(tx, &TaskSessionInterval{
+				SessionID:   next.ID,
+				StartTime:   at,
+				EndTime:     tsi.EndTime,
+				PauseReason: tsi.PauseReason,
+			})
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		cut := at
+		tsi.EndTime = &cut
+		if err := m.TaskSessionIntervals.UpdateTX(tx, tsi); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	cut := at
+	ts.EndedAt = &cut
+	if err := m.TaskSessions.UpdateTX(tx, ts); err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.SessionTags.Create(tx, next.ID, tags); err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.SessionKPI.Create(tx, next.ID, kpis); err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.Pomodoros.MoveTX(tx, ts.ID, next.ID, at); err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.SessionEvents.MoveTX(tx, ts.ID, next.ID, at); err != nil {
+		return nil, 0, err
+	}
+
+	if err := m.SessionNotes.MoveTX(tx, ts.ID, next.ID, at); err != nil {
+		return nil, 0, err
+	}
+
+	// Commits whose date can't be read stay with the earlier session.
+	moved := 0
+	for _, c := range commits {
+		date, err := ParseCommitDate(c.Date)
+		if err != nil || date.Before(at) {
+			continue
+		}
+		if err := m.Commits.ReassignTX(tx, c.ID, next.ID); err != nil {
+			return nil, 0, err
+		}
+		moved++
+	}
+
+	return next, moved, nil
+}
+
+// MergeSessions folds sessions into the earliest of them. They must all
+// belong to the same task. The merged session spans all of them, the time
+// between them counts as paused, notes are joined and intervals, pomodoros,
+// events and commits move over. The other sessions are deleted; tags and KPIs
+// are left to the caller. It returns the session that was kept.
+func (m Models) MergeSessions(tx *sql.Tx, sessions []*TaskSession) (*TaskSession, error) {
+	if len(sessions) < 2 {
+		return nil, errors.New("at least two sessions are needed to merge")
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].StartedAt.Before(sessions[j].StartedAt)
+	})
+
+	target := sessions[0]
+	now := time.Now()
+
+	var (
+		notes     []string
+		intervals []*TaskSessionInterval
+		open      *TaskSessionInterval
+	)
+
+	for _, ts := range sessions {
+		if ts.TaskID != target.TaskID {
+			return nil, fmt.Errorf("session #%d belongs to a different task", ts.ID)
+		}
+
+		if ts.Workspace != target.Workspace {
+			return nil, fmt.Errorf("session #%d belongs to a different project", ts.ID)
+		}
+
+		if number, err := invoiceOfTX(tx, ts.ID); err != nil {
+			return nil, err
+		} else if number != "" {
+			return nil, fmt.Errorf("session #%d is on invoice %s and can't be merged", ts.ID, number)
+		}
+
+		if ts.Notes != "" {
+			notes = append(notes, ts.Notes)
+		}
+
+		if ts.EndedAt == nil {
+			target.EndedAt = nil
+		} else if target.EndedAt != nil && ts.EndedAt.After(*target.EndedAt) {
+			target.EndedAt = ts.EndedAt
+		}
+
+		list, err := m.TaskSessionIntervals.GetAllForSession(ts.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tsi := range list {
+			c := *tsi
+			if c.EndTime == nil {
+				open = &c
+				c.EndTime = &now
+			}
+			intervals = append(intervals, &c)
+		}
+	}
+
+	sessionEnd := now
+	if target.EndedAt != nil {
+		sessionEnd = *target.EndedAt
+	}
+
+	if err := ValidateIntervals(target.StartedAt, sessionEnd, intervals); err != nil {
+		return nil, err
+	}
+
+	if open != nil && intervals[len(intervals)-1].ID != open.ID {
+		return nil, errors.New("only the latest interval can be open")
+	}
+
+	target.Notes = strings.Join(notes, "\n")
+	if err := m.TaskSessions.UpdateTX(tx, target); err != nil {
+		return nil, err
+	}
+
+	for _, ts := range sessions[1:] {
+		if err := m.TaskSessionIntervals.MoveTX(tx, ts.ID, target.ID, time.Time{}); err != nil {
+			return nil, err
+		}
+		if err := m.Pomodoros.MoveTX(tx, ts.ID, target.ID, time.Time{}); err != nil {
+			return nil, err
+		}
+		if err := m.SessionEvents.MoveTX(tx, ts.ID, target.ID, time.Time{}); err != nil {
+			return nil, err
+		}
+		if err := m.SessionNotes.MoveTX(tx, ts.ID, target.ID, time.Time{}); err != nil {
+			return nil, err
+		}
+		if err := m.Commits.MoveTX(tx, ts.ID, target.ID); err != nil {
+			return nil, err
+		}
+		if err := m.TaskSessions.DeleteTX(tx, ts.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return target, nil
+}
+
+// GetDurations returns the active, paused and total time of a session. For a
+// session that is still running the open interval and the session itself
+// are counted up to now.
 func (m TaskSessionModel) GetDurations(sessionID int) (totalTime, activeTime, pausedTime time.Duration, err error) {
 	var (
-		startedAt, endedAt time.Time
-		activeSeconds      sql.NullInt64
+		startedAt     time.Time
+		endedAt       sql.NullTime
+		activeSeconds sql.NullInt64
 	)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -92,9 +623,9 @@ func (m TaskSessionModel) GetDurations(sessionID int) (totalTime, activeTime, pa
 
 	g.Go(func() error {
 		query := `
-			SELECT SUM(strftime('%s', end_time) - strftime('%s', start_time))
+			SELECT SUM(strftime('%s', COALESCE(end_time, CURRENT_TIMESTAMP)) - strftime('%s', start_time))
 			FROM task_session_intervals
-			WHERE session_id = ? AND end_time IS NOT NULL
+			WHERE session_id = ?
 		`
 		return m.DB.QueryRowContext(ctx, query, sessionID).Scan(&activeSeconds)
 	})
@@ -103,18 +634,26 @@ func (m TaskSessionModel) GetDurations(sessionID int) (totalTime, activeTime, pa
 		return 0, 0, 0, err
 	}
 
-	if endedAt.IsZero() {
-		return 0, 0, 0, fmt.Errorf("session is still active; stop it first to calculate durations")
+	end := time.Now()
+	if endedAt.Valid {
+		end = endedAt.Time
 	}
 
-	totalTime = endedAt.Sub(startedAt)
+	totalTime = end.Sub(startedAt).Truncate(time.Second)
 	activeTime = time.Duration(activeSeconds.Int64) * time.Second
 	pausedTime = totalTime - activeTime
 
+	// The database clock and ours can be a second apart.
+	if pausedTime < 0 {
+		pausedTime = 0
+	}
+
 	return activeTime, pausedTime, totalTime, nil
 }
 
-func CreateTaskAndSession(db *sql.DB, description string) (*Task, *TaskSession, error) {
+// CreateTaskAndSession opens a session for task in workspace, inserting the
+// task first unless it already has an ID.
+func CreateTaskAndSession(db *sql.DB, task *Task, workspace string) (*Task, *TaskSession, error) {
 	tx, err := db.Begin()
 	if err != nil {
 		return nil, nil, fmt.Errorf("couldn't start transaction: %w", err)
@@ -123,27 +662,34 @@ func CreateTaskAndSession(db *sql.DB, description string) (*Task, *TaskSession,
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	query := `
-		INSERT INTO tasks (description)
-		VALUES (?)
-		RETURNING id, description, created_at
-	`
-	task := &Task{}
-	err = tx.QueryRowContext(ctx, query, description).Scan(&task.ID, &task.Description, &task.CreatedAt)
+	if task.ID == 0 {
+		query := `
+			INSERT INTO tasks (description)
+			VALUES (?)
+			RETURNING id, description, created_at
+		`
+		err = tx.QueryRowContext(ctx, query, task.Description).Scan(&task.ID, &task.Description, &task.CreatedAt)
 
-	if err != nil {
-		tx.Rollback()
-		return nil, nil, fmt.Errorf("failed to insert task: %w", err)
+		if err != nil {
+			tx.Rollback()
+			return nil, nil, fmt.Errorf("failed to insert task: %w", err)
+		}
 	}
 
-	query = `
-		INSERT INTO task_sessions (task_id)
-		VALUES (?)
-		RETURNING id, task_id, started_at, ended_at
+	query := `
+		INSERT INTO task_sessions (task_id, workspace)
+		VALUES (?, ?)
+		RETURNING id, task_id, started_at, ended_at, workspace
 	`
 
 	session := &TaskSession{}
-	err = tx.QueryRowContext(ctx, query, task.ID).Scan(&session.ID, &session.TaskID, &session.StartedAt, &session.EndedAt)
+	err = tx.QueryRowContext(ctx, query, task.ID, workspace).Scan(
+		&session.ID,
+		&session.TaskID,
+		&session.StartedAt,
+		&session.EndedAt,
+		&session.Workspace,
+	)
 
 	if err != nil {
 		tx.Rollback()
@@ -163,7 +709,7 @@ func GetSummaryStats(db *sql.DB) (*SummaryStats, error) {
 	var stats SummaryStats
 	var firstErr error
 
-	wg.Add(4)
+	wg.Add(5)
 
 	go func() {
 		defer wg.Done()
@@ -213,83 +759,82 @@ func GetSummaryStats(db *sql.DB) (*SummaryStats, error) {
 		mu.Unlock()
 	}()
 
+	go func() {
+		defer wg.Done()
+		val, change, err := GetWeekOvertime(db)
+		if err != nil {
+			setErr(&firstErr, err)
+			return
+		}
+		mu.Lock()
+		stats.WeekOvertime = MetricStat{Value: val, Change: change}
+		mu.Unlock()
+	}()
+
 	wg.Wait()
+	stats.Rounding = RoundingLabel()
 	return &stats, firstErr
 }
 
+// activeHoursQuery sums the part of every interval that falls between two
+// instants. Open intervals run until now.
+const activeHoursQuery = `
+	SELECT COALESCE(SUM(MAX(0,
+		strftime('%s', MIN(COALESCE(end_time, CURRENT_TIMESTAMP), ?))
+		- strftime('%s', MAX(start_time, ?))
+	)), 0) / 3600.0
+	FROM task_session_intervals
+	WHERE
+		start_time < ? AND
+		COALESCE(end_time, CURRENT_TIMESTAMP) > ?
+`
+
+func activeHours(ctx context.Context, db *sql.DB, from, to time.Time) (float64, error) {
+	if CurrentRounding() != nil {
+		pieces, err := loadPieces(ctx, db, from, to)
+		if err != nil {
+			return 0, err
+		}
+		return roundedTotal(pieces).Hours(), nil
+	}
+
+	var hours float64
+	start, end := formatTime(from), formatTime(to)
+	err := db.QueryRowContext(ctx, activeHoursQuery, end, start, end, start).Scan(&hours)
+	return hours, err
+}
+
 func GetTodayHours(db *sql.DB) (float64, float64, error) {
-	var today float64
-	var yesterday float64
-
-	todayQuery := `
-		SELECT COALESCE(SUM(
-			(strftime('%s', MIN(COALESCE(end_time, CURRENT_TIMESTAMP), DATETIME('now', 'start of day', '+1 day', 'localtime')))
-			- strftime('%s', MAX(start_time, DATETIME('now', 'start of day', 'localtime')))) / 3600.0
-		), 0)
-		FROM task_session_intervals
-		WHERE
-			start_time < DATETIME('now', 'start of day', '+1 day', 'localtime') AND
-			COALESCE(end_time, CURRENT_TIMESTAMP) > DATETIME('now', 'start of day', 'localtime')
-	`
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := db.QueryRowContext(ctx, todayQuery).Scan(&today)
+	today := startOfDay(time.Now())
+
+	todayHours, err := activeHours(ctx, db, today, today.AddDate(0, 0, 1))
 	if err != nil {
 		return 0, 0, err
 	}
 
-	yesterdayQuery := `
-		SELECT COALESCE(SUM(
-			(strftime('%s', MIN(end_time, DATETIME('now', 'start of day')))
-			- strftime('%s', MAX(start_time, DATETIME('now', '-1 day', 'start of day')))) / 3600.0
-		), 0)
-		FROM task_session_intervals
-		WHERE
-			start_time < DATETIME('now', 'start of day') AND
-			end_time > DATETIME('now', '-1 day', 'start of day')
-	`
-	err = db.QueryRowContext(ctx, yesterdayQuery).Scan(&yesterday)
+	yesterdayHours, err := activeHours(ctx, db, today.AddDate(0, 0, -1), today)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	return math.Round(today), calculateChange(today, yesterday), nil
+	return math.Round(todayHours), calculateChange(todayHours, yesterdayHours), nil
 }
 
 func GetWeekHours(db *sql.DB) (float64, float64, error) {
-	var currentWeek float64
-	var previousWeek float64
-
-	currentWeekQuery := `
-		SELECT COALESCE(SUM(
-			(strftime('%s', COALESCE(end_time, DATETIME('now'))) 
-			- strftime('%s', start_time)) / 3600.0
-		), 0)
-		FROM task_session_intervals
-		WHERE 
-			start_time < DATETIME('now', 'weekday 1', 'start of day') AND
-			(end_time IS NULL OR end_time >= DATETIME('now', 'weekday 1', '-7 days', 'start of day'));
-	`
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := db.QueryRowContext(ctx, currentWeekQuery).Scan(&currentWeek)
+	week := startOfWeek(time.Now())
+
+	currentWeek, err := activeHours(ctx, db, week, week.AddDate(0, 0, 7))
 	if err != nil {
 		return 0, 0, err
 	}
 
-	previousWeekQuery := `
-		SELECT COALESCE(SUM(
-			(strftime('%s', COALESCE(end_time, DATETIME('now', 'weekday 1', '-7 days', 'start of day'))) 
-			- strftime('%s', start_time)) / 3600.0
-		), 0)
-		FROM task_session_intervals
-		WHERE 
-			start_time < DATETIME('now', 'weekday 1', '-7 days', 'start of day') AND
-			(end_time IS NULL OR end_time >= DATETIME('now', 'weekday 1', '-14 days', 'start of day'));
-	`
-	err = db.QueryRowContext(ctx, previousWeekQuery).Scan(&previousWeek)
+	previousWeek, err := activeHours(ctx, db, week.AddDate(0, 0, -7), week)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -297,6 +842,25 @@ func GetWeekHours(db *sql.DB) (float64, float64, error) {
 	return math.Round(currentWeek), calculateChange(currentWeek, previousWeek), nil
 }
 
+// GetWeekOvertime returns the hours worked outside the schedule this week
+// and the change against last week.
+func GetWeekOvertime(db *sql.DB) (float64, float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	week := startOfWeek(time.Now())
+
+	hours, err := overtimeHours(ctx, db, []period{
+		{start: week.AddDate(0, 0, -7), end: week},
+		{start: week, end: week.AddDate(0, 0, 7)},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return math.Round(hours[1]*100) / 100, calculateChange(hours[1], hours[0]), nil
+}
+
 func GetTodaySessions(db *sql.DB) (int, float64, error) {
 	var currentDay int
 	var previousDay int
@@ -305,26 +869,23 @@ func GetTodaySessions(db *sql.DB) (int, float64, error) {
 		SELECT COUNT(*)
 		FROM task_sessions
 		WHERE
-			started_at < DATETIME('now', '+1 day', 'start of day') AND
-			COALESCE(ended_at, CURRENT_TIMESTAMP) >= DATETIME('now', 'start of day')
+			started_at < ? AND
+			COALESCE(ended_at, CURRENT_TIMESTAMP) >= ?
 	`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := db.QueryRowContext(ctx, query).Scan(&currentDay)
+	today := startOfDay(time.Now())
+	tomorrow := today.AddDate(0, 0, 1)
+	yesterday := today.AddDate(0, 0, -1)
+
+	err := db.QueryRowContext(ctx, query, formatTime(tomorrow), formatTime(today)).Scan(&currentDay)
 	if err != nil {
 		return 0, 0, err
 	}
 
-	query = `
-		SELECT COUNT(*)
-		FROM task_sessions
-		WHERE
-			started_at < DATETIME('now', 'start of day') AND
-			COALESCE(ended_at, CURRENT_TIMESTAMP) >= DATETIME('now', '-1 day', 'start of day')
-	`
-	err = db.QueryRowContext(ctx, query).Scan(&previousDay)
+	err = db.QueryRowContext(ctx, query, formatTime(today), formatTime(yesterday)).Scan(&previousDay)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -336,18 +897,14 @@ func GetProductivityScore(db *sql.DB) (float64, float64, error) {
 	getScoreQuery := `
 		WITH daily_intervals AS (
 			SELECT
-				CASE 
-					WHEN strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATE(?, '+1 day'))) -
-						 strftime('%s', MAX(start_time, DATE(?))) > 0
-					THEN strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATE(?, '+1 day'))) -
-						 strftime('%s', MAX(start_time, DATE(?)))
-					ELSE 0
-				END AS duration_sec
+				MAX(0,
+					strftime('%s', MIN(COALESCE(end_time, CURRENT_TIMESTAMP), ?)) -
+					strftime('%s', MAX(start_time, ?))
+				) AS duration_sec
 			FROM task_session_intervals
 			WHERE
-				start_time < DATE(?, '+1 day') AND
-				COALESCE(end_time, DATETIME('now')) > DATE(?)
-			GROUP BY id
+				start_time < ? AND
+				COALESCE(end_time, CURRENT_TIMESTAMP) > ?
 		),
 		productive AS (
 			SELECT COALESCE(SUM(duration_sec), 0) AS total_productive 
@@ -372,21 +929,26 @@ func GetProductivityScore(db *sql.DB) (float64, float64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	today := time.Now().Format("2006-01-02")
-	var score float64
-	err := db.QueryRowContext(ctx, getScoreQuery, today, today, today, today, today, today).Scan(&score)
+	score := func(day time.Time) (float64, error) {
+		var value float64
+		start, end := formatTime(day), formatTime(day.AddDate(0, 0, 1))
+		err := db.QueryRowContext(ctx, getScoreQuery, end, start, end, start).Scan(&value)
+		return value, err
+	}
+
+	today := startOfDay(time.Now())
+
+	todayScore, err := score(today)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get today's score: %w", err)
 	}
 
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-	var lastScore float64
-	err = db.QueryRowContext(ctx, getScoreQuery, yesterday, yesterday, yesterday, yesterday, yesterday, yesterday).Scan(&lastScore)
+	lastScore, err := score(today.AddDate(0, 0, -1))
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get yesterday's score: %w", err)
 	}
 
-	return score, calculateChange(score, lastScore), nil
+	return todayScore, calculateChange(todayScore, lastScore), nil
 }
 
 func calculateChange(current, previous float64) float64 {
@@ -407,182 +969,168 @@ func setErr(dst *error, err error) {
 	}
 }
 
-func GetDailyStats(db *sql.DB) ([]*DailyStat, error) {
+// period is a reporting bucket in the report timezone.
+type period struct {
+	label      string
+	start, end time.Time
+}
+
+type periodStat struct {
+	label    string
+	sessions int
+	hours    float64
+	overtime float64
+}
+
+// getPeriodStats returns the number of sessions worked on, the hours logged
+// and the hours worked outside the schedule in each period, in the order
+// given. Intervals crossing a period boundary are split between the periods.
+func getPeriodStats(ctx context.Context, db *sql.DB, periods []period) ([]periodStat, error) {
+	values := make([]string, len(periods))
+	args := make([]any, 0, len(periods)*4)
+	for i, p := range periods {
+		values[i] = "(?, ?, ?, ?)"
+		args = append(args, i, p.label, formatTime(p.start), formatTime(p.end))
+	}
+
 	query := `
-		WITH RECURSIVE days(day) AS (
-			SELECT DATE('now', '-6 days')
-			UNION ALL
-			SELECT DATE(day, '+1 day')
-			FROM days
-			WHERE day < DATE('now')
-		),
-		intervals AS (
-			SELECT 
-				days.day AS period,
-				session_id,
-				CASE 
-					WHEN strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATETIME(days.day, '+1 day'))) -
-						strftime('%s', MAX(start_time, DATETIME(days.day))) > 0
-					THEN (strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATETIME(days.day, '+1 day'))) -
-						strftime('%s', MAX(start_time, DATETIME(days.day)))) / 3600.0
-					ELSE 0
-					END AS duration
-			FROM task_session_intervals
-			CROSS JOIN days
-			WHERE start_time < DATETIME(days.day, '+1 day') 
-				AND COALESCE(end_time, DATETIME('now')) > days.day
-			GROUP BY days.day, session_id
+		WITH periods(position, label, start_at, end_at) AS (
+			VALUES ` + strings.Join(values, ", ") + `
 		)
-		SELECT 
-			period,
-			COUNT(DISTINCT session_id) AS sessions,
-			ROUND(SUM(duration), 2) AS hours
-		FROM intervals
-		WHERE duration > 0
-		GROUP BY period
-		ORDER BY period;
+		SELECT
+			p.label,
+			COUNT(DISTINCT tsi.session_id) AS sessions,
+			ROUND(COALESCE(SUM(MAX(0,
+				strftime('%s', MIN(COALESCE(tsi.end_time, CURRENT_TIMESTAMP), p.end_at))
+				- strftime('%s', MAX(tsi.start_time, p.start_at))
+			)), 0) / 3600.0, 2) AS hours
+		FROM periods p
+		LEFT JOIN task_session_intervals tsi ON
+			tsi.start_time < p.end_at AND
+			COALESCE(tsi.end_time, CURRENT_TIMESTAMP) > p.start_at
+		GROUP BY p.position, p.label
+		ORDER BY p.position;
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
 	defer rows.Close()
 
-	stats := make([]*DailyStat, 0)
-
+	var stats []periodStat
 	for rows.Next() {
-		var stat DailyStat
-		if err := rows.Scan(&stat.Date, &stat.Sessions, &stat.Hours); err != nil {
+		var stat periodStat
+		if err := rows.Scan(&stat.label, &stat.sessions, &stat.hours); err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
-		stats = append(stats, &stat)
+		stats = append(stats, stat)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("row iteration failed: %w", err)
 	}
 
+	overtime, err := overtimeHours(ctx, db, periods)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats {
+		stats[i].overtime = math.Round(overtime[i]*100) / 100
+	}
+
+	if CurrentRounding() != nil && len(periods) > 0 {
+		pieces, err := loadPieces(ctx, db, periods[0].start, periods[len(periods)-1].end)
+		if err != nil {
+			return nil, err
+		}
+		for i, p := range periods {
+			stats[i].hours = roundedHours(clipPieces(pieces, p.start, p.end))
+		}
+	}
+
 	return stats, nil
 }
 
-func GetWeeklyStats(db *sql.DB) ([]*WeeklyStat, error) {
-	query := `
-		WITH RECURSIVE weeks(start_date) AS (
-				SELECT DATE('now', 'weekday 1', '-21 days')
-				UNION ALL
-				SELECT DATE(start_date, '+7 days')
-				FROM weeks
-				WHERE start_date < DATE('now', 'weekday 1')
-		),
-		intervals AS (
-				SELECT 
-						weeks.start_date AS week_start,
-						session_id,
-						CASE 
-								WHEN strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATETIME(weeks.start_date, '+7 days'))) -
-										strftime('%s', MAX(start_time, DATETIME(weeks.start_date))) > 0
-								THEN (strftime('%s', MIN(COALESCE(end_time, DATETIME('now')), DATETIME(weeks.start_date, '+7 days'))) -
-											strftime('%s', MAX(start_time, DATETIME(weeks.start_date)))) / 3600.0
-								ELSE 0
-						END AS duration
-				FROM task_session_intervals
-				CROSS JOIN weeks
-				WHERE start_time < DATETIME(weeks.start_date, '+7 days')
-					AND COALESCE(end_time, DATETIME('now')) > weeks.start_date
-				GROUP BY weeks.start_date, session_id
-		)
-		SELECT 
-				week_start,
-				COUNT(DISTINCT session_id) AS sessions,
-				ROUND(SUM(duration), 2) AS hours
-		FROM intervals
-		WHERE duration > 0
-		GROUP BY week_start
-		ORDER BY week_start;
-	`
+// GetDailyStats returns the last seven days, today included. Days without
+// work are left out.
+func GetDailyStats(db *sql.DB) ([]*DailyStat, error) {
+	today := startOfDay(time.Now())
+
+	var periods []period
+	for i := 6; i >= 0; i-- {
+		day := today.AddDate(0, 0, -i)
+		periods = append(periods, period{label: day.Format("2006-01-02"), start: day, end: day.AddDate(0, 0, 1)})
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := getPeriodStats(ctx, db, periods)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	stats := make([]*WeeklyStat, 0)
 
-	for rows.Next() {
-		var ws WeeklyStat
-		if err := rows.Scan(&ws.Start, &ws.Sessions, &ws.Hours); err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
+	stats := make([]*DailyStat, 0)
+	for _, row := range rows {
+		if row.hours > 0 {
+			stats = append(stats, &DailyStat{Date: row.label, Hours: row.hours, Overtime: row.overtime, Sessions: row.sessions})
 		}
-		stats = append(stats, &ws)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration failed: %w", err)
+	return stats, nil
+}
+
+// GetWeeklyStats returns the last four weeks, starting on Mondays, the
+// current week included. Weeks without work are left out.
+func GetWeeklyStats(db *sql.DB) ([]*WeeklyStat, error) {
+	thisWeek := startOfWeek(time.Now())
+
+	var periods []period
+	for i := 3; i >= 0; i-- {
+		week := thisWeek.AddDate(0, 0, -7*i)
+		periods = append(periods, period{label: week.Format("2006-01-02"), start: week, end: week.AddDate(0, 0, 7)})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := getPeriodStats(ctx, db, periods)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*WeeklyStat, 0)
+	for _, row := range rows {
+		if row.hours > 0 {
+			stats = append(stats, &WeeklyStat{Start: row.label, Hours: row.hours, Overtime: row.overtime, Sessions: row.sessions})
+		}
 	}
 
 	return stats, nil
 }
 
+// GetMonthlyStats returns the last three months, the current month included.
 func GetMonthlyStats(db *sql.DB) ([]*MonthlyStat, error) {
-	query := `
-		WITH RECURSIVE months(month_start) AS (
-				SELECT DATE('now', 'start of month', '-2 months')
-				UNION ALL
-				SELECT DATE(month_start, '+1 month')
-				FROM months
-				WHERE month_start < DATE('now', 'start of month')
-		)
-		SELECT
-				month_start,
-				COUNT(DISTINCT tsi.session_id) AS sessions,
-				ROUND(COALESCE(SUM(
-						CASE 
-								WHEN strftime('%s', MIN(COALESCE(tsi.end_time, DATETIME('now')), DATE(month_start, '+1 month'))) -
-										strftime('%s', MAX(tsi.start_time, month_start)) > 0
-								THEN CAST((
-										strftime('%s', MIN(COALESCE(tsi.end_time, DATETIME('now')), DATE(month_start, '+1 month'))) -
-										strftime('%s', MAX(tsi.start_time, month_start))
-								) AS REAL) / 3600.0
-								ELSE 0
-						END
-				), 0), 2) AS hours
-		FROM months
-		LEFT JOIN task_session_intervals tsi ON
-				tsi.start_time < DATE(month_start, '+1 month') AND
-				COALESCE(tsi.end_time, DATETIME('now')) > month_start
-		GROUP BY month_start
-		ORDER BY month_start;
-	`
+	thisMonth := startOfMonth(time.Now())
+
+	var periods []period
+	for i := 2; i >= 0; i-- {
+		month := thisMonth.AddDate(0, -i, 0)
+		periods = append(periods, period{label: month.Format("2006-01-02"), start: month, end: month.AddDate(0, 1, 0)})
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	rows, err := db.QueryContext(ctx, query)
+	rows, err := getPeriodStats(ctx, db, periods)
 	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
+		return nil, err
 	}
-	defer rows.Close()
-
-	stats := make([]*MonthlyStat, 0)
 
-	for rows.Next() {
-		var ms MonthlyStat
-		if err := rows.Scan(&ms.Month, &ms.Sessions, &ms.Hours); err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
-		}
-		stats = append(stats, &ms)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("row iteration failed: %w", err)
+	stats := make([]*MonthlyStat, 0, len(rows))
+	for _, row := range rows {
+		stats = append(stats, &MonthlyStat{Month: row.label, Hours: row.hours, Overtime: row.overtime, Sessions: row.sessions})
 	}
 
 	return stats, nil
@@ -595,7 +1143,7 @@ func parseWeekStart(period string) (time.Time, error) {
 		return time.Time{}, err
 	}
 
-	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.Local)
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, Location())
 	weekday := int(jan4.Weekday())
 	if weekday == 0 {
 		weekday = 7
@@ -606,11 +1154,67 @@ func parseWeekStart(period string) (time.Time, error) {
 	return weekStart, nil
 }
 
+// displayTime rewrites a stored UTC timestamp as RFC 3339 in the report
+// timezone. Values that can't be parsed are passed through unchanged.
+func displayTime(stored string) string {
+	t, err := parseStoredTime(stored)
+	if err != nil {
+		return stored
+	}
+	return t.In(Location()).Format(time.RFC3339)
+}
+
 func GetSessions(db *sql.DB) ([]*Session, error) {
+	return querySessions(db, 0)
+}
+
+// GetSession returns one session with its notes and commits, or
+// ErrRecordNotFound.
+func GetSession(db *sql.DB, id int) (*SessionDetail, error) {
+	sessions, err := querySessions(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrRecordNotFound
+	}
+
+	notes, err := SessionNoteModel{db}.GetBySession(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes: %w", err)
+	}
+	for _, n := range notes {
+		n.NotedAt = n.NotedAt.In(Location())
+		n.CreatedAt = n.CreatedAt.In(Location())
+	}
+
+	commits, err := CommitModel{db}.GetBySession(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
+	}
+
+	detail := &SessionDetail{
+		Session: sessions[0],
+		Notes:   notes,
+		Commits: make([]*SessionCommit, 0, len(commits)),
+	}
+	for _, c := range commits {
+		detail.Commits = append(detail.Commits, &SessionCommit{Hash: c.Hash, Message: c.Message, Author: c.Author, Date: c.Date, DiffStat: c.DiffStat})
+	}
+
+	return detail, nil
+}
+
+// querySessions lists sessions with their duration and churn, newest first:
+// every session, or only sessionID when it isn't 0.
+func querySessions(db *sql.DB, sessionID int) ([]*Session, error) {
 	query := `
 		SELECT 
 			ts.id,
 			t.description,
+			COALESCE(p.name, '') AS project,
+			COALESCE(parent.description, '') AS parent,
+			t.status AS task_status,
 			MIN(ti.start_time) AS start_time,
 			MAX(ti.end_time) AS last_interval_end,
 			ts.ended_at,
@@ -624,10 +1228,16 @@ func GetSessions(db *sql.DB) ([]*Session, error) {
 					THEN (strftime('%s', ti.end_time) - strftime('%s', ti.start_time))
 					ELSE (strftime('%s', DATETIME('now')) - strftime('%s', ti.start_time))
 				END
-			) AS total_seconds
+			) AS total_seconds,
+			(SELECT IFNULL(SUM(c.files_changed), 0) FROM commits c WHERE c.session_id = ts.id) AS files_changed,
+			(SELECT IFNULL(SUM(c.insertions), 0) FROM commits c WHERE c.session_id = ts.id) AS insertions,
+			(SELECT IFNULL(SUM(c.deletions), 0) FROM commits c WHERE c.session_id = ts.id) AS deletions
 		FROM task_sessions ts
 		JOIN tasks t ON ts.task_id = t.id
+		LEFT JOIN projects p ON p.id = t.project_id
+		LEFT JOIN tasks parent ON parent.id = t.parent_id
 		JOIN task_session_intervals ti ON ti.session_id = ts.id
+		WHERE ? = 0 OR ts.id = ?
 		GROUP BY ts.id, t.description, ts.ended_at
 		ORDER BY start_time DESC
 	`
@@ -635,7 +1245,24 @@ func GetSessions(db *sql.DB) ([]*Session, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	rows, err := db.QueryContext(ctx, query)
+	// With rounding, durations are worked out from the intervals instead.
+	var bySession map[int][]piece
+	if CurrentRounding() != nil {
+		var only []int
+		if sessionID != 0 {
+			only = append(only, sessionID)
+		}
+		pieces, err := loadPieces(ctx, db, time.Time{}, time.Now(), only...)
+		if err != nil {
+			return nil, err
+		}
+		bySession = make(map[int][]piece)
+		for _, p := range pieces {
+			bySession[p.session] = append(bySession[p.session], p)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query, sessionID, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("query failed: %w", err)
 	}
@@ -653,11 +1280,15 @@ func GetSessions(db *sql.DB) ([]*Session, error) {
 			totalSeconds int64
 		)
 
-		err := rows.Scan(&s.ID, &s.Task, &startTime, &endTime, &endedAt, &hasActive, &totalSeconds)
+		err := rows.Scan(&s.ID, &s.Task, &s.Project, &s.Parent, &s.TaskStatus, &startTime, &endTime, &endedAt, &hasActive, &totalSeconds, &s.Files, &s.Insertions, &s.Deletions)
 		if err != nil {
 			return nil, fmt.Errorf("scan error: %w", err)
 		}
 
+		if bySession != nil {
+			totalSeconds = int64(roundedTotal(bySession[s.ID]).Seconds())
+		}
+
 		hours := totalSeconds / 3600
 		minutes := (totalSeconds % 3600) / 60
 
@@ -667,10 +1298,11 @@ func GetSessions(db *sql.DB) ([]*Session, error) {
 			s.Duration = fmt.Sprintf("%dm", minutes)
 		}
 
-		s.StartTime = startTime
+		s.StartTime = displayTime(startTime)
 
 		if endTime.Valid {
-			s.EndTime = &endTime.String
+			end := displayTime(endTime.String)
+			s.EndTime = &end
 		} else {
 			s.EndTime = nil
 		}
@@ -693,7 +1325,26 @@ func GetSessions(db *sql.DB) ([]*Session, error) {
 	return sessions, nil
 }
 
+// ExportToCSV writes every report to filename, as WriteCSV does.
 func ExportToCSV(db *sql.DB, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := WriteCSV(db, file); err != nil {
+		return err
+	}
+
+	fmt.Printf("Exported to %s\n", filename)
+	return file.Close()
+}
+
+// WriteCSV writes the summary, period stats, project and parent task
+// rollups, sessions and notes to w as one CSV, a section after another.
+// Everything is loaded before the first row is written.
+func WriteCSV(db *sql.DB, w io.Writer) error {
 	summary, err := GetSummaryStats(db)
 	if err != nil {
 		return err
@@ -714,15 +1365,20 @@ func ExportToCSV(db *sql.DB, filename string) error {
 	if err != nil {
 		return err
 	}
-
-	file, err := os.Create(filename)
+	projects, err := GetProjectTotals(db)
 	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
+		return err
+	}
+	parents, err := GetParentTotals(db)
+	if err != nil {
+		return err
+	}
+	notes, err := SessionNoteModel{db}.GetAll()
+	if err != nil {
+		return err
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	writer := csv.NewWriter(w)
 
 	// Summary
 	writer.Write([]string{"Section", "Metric", "Value", "Change"})
@@ -730,6 +1386,7 @@ func ExportToCSV(db *sql.DB, filename string) error {
 	writer.Write([]string{"Summary", "Week Hours", fmt.Sprintf("%v", summary.WeekHours.Value), fmt.Sprintf("%v", summary.WeekHours.Change)})
 	writer.Write([]string{"Summary", "Sessions Today", fmt.Sprintf("%v", summary.SessionsToday.Value), fmt.Sprintf("%v", summary.SessionsToday.Change)})
 	writer.Write([]string{"Summary", "Productivity Score", fmt.Sprintf("%v", summary.ProductivityScore.Value), fmt.Sprintf("%v", summary.ProductivityScore.Change)})
+	writer.Write([]string{"Summary", "Rounding", summary.Rounding, ""})
 
 	// Daily Stats
 	writer.Write([]string{})
@@ -752,17 +1409,43 @@ func ExportToCSV(db *sql.DB, filename string) error {
 		writer.Write([]string{"Monthly", m.Month, fmt.Sprintf("%.2f", m.Hours), fmt.Sprintf("%d", m.Sessions)})
 	}
 
+	// Projects
+	writer.Write([]string{})
+	writer.Write([]string{"Section", "Project", "Client", "Hours", "Tasks", "Sessions"})
+	for _, p := range projects {
+		writer.Write([]string{"Project", p.Name, p.Client, fmt.Sprintf("%.2f", p.Hours), fmt.Sprintf("%d", p.Tasks), fmt.Sprintf("%d", p.Sessions)})
+	}
+
+	// Parent Tasks
+	writer.Write([]string{})
+	writer.Write([]string{"Section", "Task", "Project", "Hours", "Own Hours", "Subtasks"})
+	for _, p := range parents {
+		writer.Write([]string{"Parent Task", p.Task, p.Project, fmt.Sprintf("%.2f", p.Hours), fmt.Sprintf("%.2f", p.OwnHours), fmt.Sprintf("%d", p.Subtasks)})
+	}
+
 	// Sessions
 	writer.Write([]string{})
-	writer.Write([]string{"Section", "Task", "Start Time", "End Time", "Duration", "Status"})
+	writer.Write([]string{"Section", "Task", "Project", "Parent Task", "Start Time", "End Time", "Duration", "Status", "Files Changed", "Insertions", "Deletions"})
 	for _, s := range sessions {
 		end := ""
 		if s.EndTime != nil {
 			end = *s.EndTime
 		}
-		writer.Write([]string{"Session", s.Task, s.StartTime, end, s.Duration, s.Status})
+		writer.Write([]string{"Session", s.Task, s.Project, s.Parent, s.StartTime, end, s.Duration, s.Status, fmt.Sprintf("%d", s.Files), fmt.Sprintf("%d", s.Insertions), fmt.Sprintf("%d", s.Deletions)})
 	}
 
-	fmt.Printf("Exported to %s\n", filename)
-	return nil
+	// Notes
+	tasks := make(map[int]string, len(sessions))
+	for _, s := range sessions {
+		tasks[s.ID] = s.Task
+	}
+
+	writer.Write([]string{})
+	writer.Write([]string{"Section", "Session", "Task", "Time", "Note"})
+	for _, n := range notes {
+		writer.Write([]string{"Note", fmt.Sprintf("%d", n.SessionID), tasks[n.SessionID], n.NotedAt.In(Location()).Format(time.RFC3339), n.Body})
+	}
+
+	writer.Flush()
+	return writer.Error()
 }
@@ -0,0 +1,375 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitSession(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	task := seedTask(t, db, "Before")
+	ts := seedSession(t, db, task, "a",
+		span{"2025-03-03 09:00", "2025-03-03 10:00"},
+		span{"2025-03-03 11:00", "2025-03-03 12:00"})
+
+	err := inTx(t, db, func(tx *sql.Tx) error {
+		return m.SessionTags.Create(tx, ts.ID, []string{"backend"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct{ hash, date string }{
+		{"early", "Mon Mar 3 09:15:00 2025 +0000"},
+		{"late", "Mon Mar 3 11:30:00 2025 +0000"},
+	} {
+		if err := m.Commits.Create(&Commit{Hash: c.hash, SessionID: &ts.ID, Date: c.date}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		next  *TaskSession
+		moved int
+	)
+	err = inTx(t, db, func(tx *sql.Tx) error {
+		var err error
+		next, moved, err = m.SplitSession(tx, ts, &Task{Description: "After"}, at(t, "2025-03-03 09:30"))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := intervalBounds(t, db, ts.ID); !reflect.DeepEqual(got, []string{"09:00-09:30"}) {
+		t.Errorf("first session intervals: got %v", got)
+	}
+	if got := intervalBounds(t, db, next.ID); !reflect.DeepEqual(got, []string{"09:30-10:00", "11:00-12:00"}) {
+		t.Errorf("second session intervals: got %v", got)
+	}
+
+	first, err := m.TaskSessions.GetByID(ts.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.EndedAt == nil || !first.EndedAt.Equal(at(t, "2025-03-03 09:30")) {
+		t.Errorf("first session ends %v, want 09:30", first.EndedAt)
+	}
+
+	second, err := m.TaskSessions.GetByID(next.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.TaskID == task || second.EndedAt == nil || !second.EndedAt.Equal(at(t, "2025-03-03 12:00")) {
+		t.Errorf("second session: got task #%d ending %v", second.TaskID, second.EndedAt)
+	}
+
+	if tags, err := m.SessionTags.GetBySession(next.ID); err != nil || !reflect.DeepEqual(tags, []string{"backend"}) {
+		t.Errorf("second session tags: got %v, %v", tags, err)
+	}
+
+	if moved != 1 {
+		t.Errorf("moved %d commits, want 1", moved)
+	}
+}
+
+func TestSplitSessionRefused(t *testing.T) {
+	tests := []struct {
+		name     string
+		split    string
+		invoiced bool
+	}{
+		{name: "at the start", split: "2025-03-03 09:00"},
+		{name: "after the end", split: "2025-03-03 13:00"},
+		{name: "invoiced", split: "2025-03-03 09:30", invoiced: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			task := seedTask(t, db, "Work")
+			ts := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 12:00"})
+			if tt.invoiced {
+				invoice(t, db, "INV-2025-0001", ts.ID)
+			}
+
+			err := inTx(t, db, func(tx *sql.Tx) error {
+				_, _, err := NewModels(db).SplitSession(tx, ts, &Task{ID: task}, at(t, tt.split))
+				return err
+			})
+			if err == nil {
+				t.Fatal("got no error")
+			}
+			if got := intervalBounds(t, db, ts.ID); !reflect.DeepEqual(got, []string{"09:00-12:00"}) {
+				t.Fatalf("intervals changed: %v", got)
+			}
+		})
+	}
+}
+
+func TestMergeSessions(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	task := seedTask(t, db, "Work")
+	later := seedSession(t, db, task, "a", span{"2025-03-03 11:00", "2025-03-03 12:00"})
+	earlier := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+
+	for id, notes := range map[int]string{earlier.ID: "one", later.ID: "two"} {
+		if _, err := db.Exec(`UPDATE task_sessions SET notes = ? WHERE id = ?`, notes, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	earlier.Notes, later.Notes = "one", "two"
+
+	var kept *TaskSession
+	err := inTx(t, db, func(tx *sql.Tx) error {
+		var err error
+		kept, err = m.MergeSessions(tx, []*TaskSession{later, earlier})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kept.ID != earlier.ID {
+		t.Fatalf("kept session #%d, want the earliest #%d", kept.ID, earlier.ID)
+	}
+	if got := intervalBounds(t, db, kept.ID); !reflect.DeepEqual(got, []string{"09:00-10:00", "11:00-12:00"}) {
+		t.Errorf("intervals: got %v", got)
+	}
+
+	merged, err := m.TaskSessions.GetByID(kept.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.EndedAt == nil || !merged.EndedAt.Equal(at(t, "2025-03-03 12:00")) {
+		t.Errorf("merged session ends %v, want 12:00", merged.EndedAt)
+	}
+	if merged.Notes != "one\ntwo" {
+		t.Errorf("notes: got %q", merged.Notes)
+	}
+
+	if _, err := m.TaskSessions.GetByID(later.ID); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("merged-away session: got %v, want %v", err, ErrRecordNotFound)
+	}
+}
+
+func TestMergeSessionsRefused(t *testing.T) {
+	tests := []struct {
+		name      string
+		otherTask bool
+		workspace string
+		second    span
+		invoiced  bool
+		wantErr   error
+		contains  string
+	}{
+		{name: "different task", otherTask: true, workspace: "a", second: span{"2025-03-03 11:00", "2025-03-03 12:00"}, contains: "different task"},
+		{name: "different project", workspace: "b", second: span{"2025-03-03 11:00", "2025-03-03 12:00"}, contains: "different project"},
+		{name: "overlapping", workspace: "a", second: span{"2025-03-03 09:30", "2025-03-03 12:00"}, wantErr: ErrOverlappingInterval},
+		{name: "invoiced", workspace: "a", second: span{"2025-03-03 11:00", "2025-03-03 12:00"}, invoiced: true, contains: "invoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+
+			task := seedTask(t, db, "Work")
+			first := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+
+			secondTask := task
+			if tt.otherTask {
+				secondTask = seedTask(t, db, "Other")
+			}
+			second := seedSession(t, db, secondTask, tt.workspace, tt.second)
+
+			if tt.invoiced {
+				invoice(t, db, "INV-2025-0001", second.ID)
+			}
+
+			err := inTx(t, db, func(tx *sql.Tx) error {
+				_, err := NewModels(db).MergeSessions(tx, []*TaskSession{first, second})
+				return err
+			})
+			switch {
+			case err == nil:
+				t.Fatal("got no error")
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			case tt.contains != "" && !strings.Contains(err.Error(), tt.contains):
+				t.Fatalf("got %v, want it to mention %q", err, tt.contains)
+			}
+
+			if _, err := NewModels(db).TaskSessions.GetByID(second.ID); err != nil {
+				t.Fatalf("second session was removed: %v", err)
+			}
+		})
+	}
+}
+
+func TestSwitchTask(t *testing.T) {
+	db := newTestDB(t)
+	m := NewModels(db)
+
+	current := seedSession(t, db, seedTask(t, db, "Work"), "a", span{"2025-03-03 09:00", ""})
+	if _, err := m.Pomodoros.Start(current.ID, at(t, "2025-03-03 09:30")); err != nil {
+		t.Fatal(err)
+	}
+
+	switchAt := at(t, "2025-03-03 10:00")
+	next := &TaskSession{Mode: "personal", Workspace: "a"}
+	err := inTx(t, db, func(tx *sql.Tx) error {
+		_, err := m.SwitchTask(tx, current.ID, &Task{Description: "Review"}, next, switchAt)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := intervalBounds(t, db, current.ID); !reflect.DeepEqual(got, []string{"09:00-10:00"}) {
+		t.Errorf("current intervals: got %v", got)
+	}
+	if got := intervalBounds(t, db, next.ID); !reflect.DeepEqual(got, []string{"10:00-…"}) {
+		t.Errorf("next intervals: got %v", got)
+	}
+
+	var status, ended string
+	err = db.QueryRow(`SELECT status, ended_at || '' FROM pomodoros WHERE session_id = ?`, current.ID).Scan(&status, &ended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != PomodoroAbandoned || ended != formatTime(switchAt) {
+		t.Errorf("pomodoro: got %s at %s, want %s at %s", status, ended, PomodoroAbandoned, formatTime(switchAt))
+	}
+}
+
+func TestUpdateSessionInvoiced(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(t *testing.T, ts *TaskSession, intervals []*TaskSessionInterval) []*TaskSessionInterval
+		allowed bool
+	}{
+		{
+			name: "notes",
+			edit: func(t *testing.T, ts *TaskSession, intervals []*TaskSessionInterval) []*TaskSessionInterval {
+				ts.Notes = "Paired with Sam"
+				return intervals
+			},
+			allowed: true,
+		},
+		{
+			name: "start",
+			edit: func(t *testing.T, ts *TaskSession, intervals []*TaskSessionInterval) []*TaskSessionInterval {
+				ts.StartedAt = at(t, "2025-03-03 09:15")
+				intervals[0].StartTime = ts.StartedAt
+				return intervals
+			},
+		},
+		{
+			name: "task",
+			edit: func(t *testing.T, ts *TaskSession, intervals []*TaskSessionInterval) []*TaskSessionInterval {
+				ts.TaskID++
+				return intervals
+			},
+		},
+		{
+			name: "pause",
+			edit: func(t *testing.T, ts *TaskSession, intervals []*TaskSessionInterval) []*TaskSessionInterval {
+				intervals, err := InsertPause(intervals, at(t, "2025-03-03 10:00"), at(t, "2025-03-03 10:30"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				return intervals
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			m := NewModels(db)
+
+			task := seedTask(t, db, "Work")
+			seedTask(t, db, "Other")
+			ts := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 12:00"})
+			invoice(t, db, "INV-2025-0001", ts.ID)
+
+			intervals, err := m.TaskSessionIntervals.GetAllForSession(ts.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			intervals = tt.edit(t, ts, intervals)
+
+			err = inTx(t, db, func(tx *sql.Tx) error {
+				return m.UpdateSession(tx, ts, intervals)
+			})
+			if tt.allowed && err != nil {
+				t.Fatalf("got %v", err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "INV-2025-0001")) {
+				t.Fatalf("got %v, want the edit refused", err)
+			}
+		})
+	}
+}
+
+func TestPeriodStatsInTimezone(t *testing.T) {
+	// Five hours behind UTC, local midnight is 05:00 UTC, so a UTC day would
+	// split the session below at the wrong time.
+	previous := Location()
+	SetLocation(time.FixedZone("UTC-5", -5*60*60))
+	t.Cleanup(func() { SetLocation(previous) })
+
+	db := newTestDB(t)
+	task := seedTask(t, db, "Late night")
+
+	stamp := func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") }
+
+	// 23:00 to 02:00 local, across yesterday's midnight and, a week earlier,
+	// across the midnight that starts last week.
+	yesterday := startOfDay(time.Now()).AddDate(0, 0, -1)
+	seedSession(t, db, task, "a", span{stamp(yesterday.Add(-time.Hour)), stamp(yesterday.Add(2 * time.Hour))})
+
+	lastWeek := startOfWeek(time.Now()).AddDate(0, 0, -7)
+	seedSession(t, db, task, "a", span{stamp(lastWeek.Add(-time.Hour)), stamp(lastWeek.Add(2 * time.Hour))})
+
+	daily, err := GetDailyStats(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := make(map[string]float64)
+	for _, d := range daily {
+		days[d.Date] = d.Hours
+	}
+	for day, want := range map[string]float64{
+		yesterday.AddDate(0, 0, -1).Format("2006-01-02"): 1,
+		yesterday.Format("2006-01-02"):                   2,
+	} {
+		if days[day] != want {
+			t.Errorf("%s: got %v hours, want %v (days %v)", day, days[day], want, days)
+		}
+	}
+
+	weekly, err := GetWeeklyStats(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weeks := make(map[string]float64)
+	for _, w := range weekly {
+		weeks[w.Start] = w.Hours
+	}
+	if got := weeks[lastWeek.AddDate(0, 0, -7).Format("2006-01-02")]; got != 1 {
+		t.Errorf("the week before last: got %v hours, want 1 (weeks %v)", got, weeks)
+	}
+	if got := weeks[lastWeek.Format("2006-01-02")]; got < 2 {
+		t.Errorf("last week: got %v hours, want at least 2 (weeks %v)", got, weeks)
+	}
+}
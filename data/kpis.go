@@ -0,0 +1,255 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// What a KPI target counts each week.
+const (
+	MetricHours    = "hours"
+	MetricSessions = "sessions"
+	MetricCommits  = "commits"
+)
+
+// KPITarget is a KPI with a weekly target.
+type KPITarget struct {
+	Name   string  `json:"name"`
+	Metric string  `json:"metric"`
+	Target float64 `json:"target"`
+}
+
+// KPIWeek is the progress on a KPI in the week starting on Week, a Monday.
+type KPIWeek struct {
+	Week    string  `json:"week"`
+	Actual  float64 `json:"actual"`
+	Percent float64 `json:"percent"`
+	Met     bool    `json:"met"`
+}
+
+type KPIProgress struct {
+	KPITarget
+	Weeks []*KPIWeek `json:"weeks"`
+}
+
+// SetTarget gives a KPI a weekly target, adding the KPI to the catalog if
+// needed.
+func (m LabelModel) SetTarget(name, metric string, target float64) error {
+	switch metric {
+	case MetricHours, MetricSessions, MetricCommits:
+	default:
+		return fmt.Errorf("unknown KPI metric %q", metric)
+	}
+
+	query := `
+		INSERT INTO labels (kind, name, target_metric, target_value)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (kind, name) DO UPDATE SET
+			target_metric = excluded.target_metric,
+			target_value = excluded.target_value
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, LabelKPI, name, metric, target)
+	return err
+}
+
+// ClearTarget removes a KPI's target, keeping the KPI.
+func (m LabelModel) ClearTarget(name string) error {
+	query := `
+		UPDATE labels
+		SET target_metric = NULL, target_value = NULL
+		WHERE kind = ? AND name = ?
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, LabelKPI, name)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Targets lists the KPIs that have a target, alphabetically.
+func (m LabelModel) Targets() ([]*KPITarget, error) {
+	query := `
+		SELECT name, target_metric, target_value
+		FROM labels
+		WHERE kind = ? AND target_metric IS NOT NULL
+		ORDER BY name
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, LabelKPI)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	targets := make([]*KPITarget, 0)
+	for rows.Next() {
+		var t KPITarget
+		if err := rows.Scan(&t.Name, &t.Metric, &t.Target); err != nil {
+			return nil, err
+		}
+		targets = append(targets, &t)
+	}
+
+	return targets, rows.Err()
+}
+
+// GetKPIReport measures every KPI with a target over the last weeks, this
+// week included, oldest first. Hours are split at week boundaries; a
+// session counts in every week it was worked on, and a commit in the week it
+// was made.
+func GetKPIReport(db *sql.DB, weeks int) ([]*KPIProgress, error) {
+	targets, err := LabelModel{db}.Targets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get KPI targets: %w", err)
+	}
+
+	report := make([]*KPIProgress, 0, len(targets))
+	if len(targets) == 0 || weeks < 1 {
+		return report, nil
+	}
+
+	thisWeek := startOfWeek(time.Now())
+	periods := make([]period, weeks)
+	for i := range periods {
+		start := thisWeek.AddDate(0, 0, -7*(weeks-1-i))
+		periods[i] = period{label: dayKey(start), start: start, end: start.AddDate(0, 0, 7)}
+	}
+
+	values := make([]string, len(periods))
+	args := make([]any, 0, len(periods)*3)
+	for i, p := range periods {
+		values[i] = "(?, ?, ?)"
+		args = append(args, i, formatTime(p.start), formatTime(p.end))
+	}
+
+	query := `
+		WITH periods(position, start_at, end_at) AS (
+			VALUES ` + strings.Join(values, ", ") + `
+		),
+		kpi_sessions AS (
+			SELECT DISTINCT session_id, kpi FROM session_kpis
+		)
+		SELECT
+			p.position,
+			ks.kpi,
+			COUNT(DISTINCT tsi.session_id),
+			COALESCE(SUM(MAX(0,
+				strftime('%s', MIN(COALESCE(tsi.end_time, CURRENT_TIMESTAMP), p.end_at))
+				- strftime('%s', MAX(tsi.start_time, p.start_at))
+			)), 0)
+		FROM periods p
+		JOIN task_session_intervals tsi ON
+			tsi.start_time < p.end_at AND
+			COALESCE(tsi.end_time, CURRENT_TIMESTAMP) > p.start_at
+		JOIN kpi_sessions ks ON ks.session_id = tsi.session_id
+		GROUP BY p.position, ks.kpi
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	type key struct {
+		position int
+		kpi      string
+	}
+	actual := make(map[string]map[key]float64)
+	for _, metric := range []string{MetricHours, MetricSessions, MetricCommits} {
+		actual[metric] = make(map[key]float64)
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			k        key
+			sessions int
+			seconds  int64
+		)
+		if err := rows.Scan(&k.position, &k.kpi, &sessions, &seconds); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		actual[MetricSessions][k] = float64(sessions)
+		actual[MetricHours][k] = float64(seconds) / 3600
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	// Commit dates are stored as git prints them, so they are bucketed here.
+	commitRows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT sk.kpi, c.id, COALESCE(c.date, '')
+		FROM commits c
+		JOIN session_kpis sk ON sk.session_id = c.session_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer commitRows.Close()
+
+	for commitRows.Next() {
+		var (
+			kpi, date string
+			id        int
+		)
+		if err := commitRows.Scan(&kpi, &id, &date); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		at, err := ParseCommitDate(date)
+		if err != nil {
+			continue
+		}
+		for i, p := range periods {
+			if !at.Before(p.start) && at.Before(p.end) {
+				actual[MetricCommits][key{i, kpi}]++
+				break
+			}
+		}
+	}
+
+	if err := commitRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	for _, t := range targets {
+		progress := &KPIProgress{KPITarget: *t, Weeks: make([]*KPIWeek, len(periods))}
+		for i, p := range periods {
+			value := math.Round(actual[t.Metric][key{i, t.Name}]*100) / 100
+			week := &KPIWeek{Week: p.label, Actual: value, Met: value >= t.Target}
+			if t.Target > 0 {
+				week.Percent = math.Round(value / t.Target * 100)
+			}
+			progress.Weeks[i] = week
+		}
+		report = append(report, progress)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,343 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Label kinds. Tags and KPIs are catalogued the same way; only the table
+// their session rows live in differs.
+const (
+	LabelTag = "tag"
+	LabelKPI = "kpi"
+)
+
+var (
+	ErrLabelExists  = errors.New("label already exists")
+	ErrUnknownLabel = errors.New("unknown label kind")
+)
+
+type LabelModel struct {
+	DB *sql.DB
+}
+
+// LabelStat is how often a tag or KPI was used and the active time of the
+// sessions it was used on.
+type LabelStat struct {
+	Name     string  `json:"name"`
+	Sessions int     `json:"sessions"`
+	Hours    float64 `json:"hours"`
+}
+
+// labelTable returns the session table and column holding labels of a kind.
+func labelTable(kind string) (string, string, error) {
+	switch kind {
+	case LabelTag:
+		return "session_tags", "tag", nil
+	case LabelKPI:
+		return "session_kpis", "kpi", nil
+	default:
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownLabel, kind)
+	}
+}
+
+// registerLabelsTx adds names to the catalog, skipping those already there.
+func registerLabelsTx(tx *sql.Tx, kind string, names []string) error {
+	for _, name := range names {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO labels (kind, name) VALUES (?, ?)`, kind, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add puts a name in the catalog without using it on a session.
+func (m LabelModel) Add(kind, name string) error {
+	if _, _, err := labelTable(kind); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `INSERT OR IGNORE INTO labels (kind, name) VALUES (?, ?)`, kind, name)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrLabelExists
+	}
+
+	return nil
+}
+
+// Names lists the catalog of a kind alphabetically.
+func (m LabelModel) Names(kind string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, `SELECT name FROM labels WHERE kind = ? ORDER BY name`, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+// Stats lists the catalog of a kind with usage counts and total time,
+// busiest first.
+func (m LabelModel) Stats(kind string) ([]*LabelStat, error) {
+	table, column, err := labelTable(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH session_seconds AS (
+			SELECT
+				session_id,
+				SUM(strftime('%s', COALESCE(end_time, CURRENT_TIMESTAMP)) - strftime('%s', start_time)) AS seconds
+			FROM task_session_intervals
+			GROUP BY session_id
+		),
+		usage AS (
+			SELECT
+				u.name,
+				COUNT(*) AS sessions,
+				COALESCE(SUM(ss.seconds), 0) AS seconds
+			FROM (SELECT DISTINCT session_id, ` + column + ` AS name FROM ` + table + `) u
+			LEFT JOIN session_seconds ss ON ss.session_id = u.session_id
+			GROUP BY u.name
+		)
+		SELECT l.name, COALESCE(u.sessions, 0), ROUND(COALESCE(u.seconds, 0) / 3600.0, 2)
+		FROM labels l
+		LEFT JOIN usage u ON u.name = l.name
+		WHERE l.kind = ?
+		ORDER BY COALESCE(u.seconds, 0) DESC, l.name
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*LabelStat, 0)
+	for rows.Next() {
+		var s LabelStat
+		if err := rows.Scan(&s.Name, &s.Sessions, &s.Hours); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stats = append(stats, &s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	return stats, nil
+}
+
+// Rename gives a label a new name on every session it was used on. The new
+// name must not be in the catalog yet; use Merge to fold into an existing
+// one.
+func (m LabelModel) Rename(kind, from, to string) (int64, error) {
+	return m.rewrite(kind, []string{from}, to, false)
+}
+
+// Merge folds labels into an existing one on every session they were used
+// on. A session that ends up with the target twice keeps it once.
+func (m LabelModel) Merge(kind string, from []string, into string) (int64, error) {
+	return m.rewrite(kind, from, into, true)
+}
+
+func (m LabelModel) rewrite(kind string, from []string, to string, merge bool) (int64, error) {
+	table, column, err := labelTable(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM labels WHERE kind = ? AND name = ?)`, kind, to).Scan(&exists); err != nil {
+		return 0, err
+	}
+	switch {
+	case merge && !exists:
+		return 0, fmt.Errorf("%w: %s", ErrRecordNotFound, to)
+	case !merge && exists:
+		return 0, fmt.Errorf("%w: %s", ErrLabelExists, to)
+	}
+
+	// A renamed label keeps its catalog entry, and with it any KPI target;
+	// merged ones give way to the target's.
+	var moved int64
+	for _, name := range from {
+		var (
+			result sql.Result
+			err    error
+		)
+		if merge {
+			result, err = tx.Exec(`DELETE FROM labels WHERE kind = ? AND name = ?`, kind, name)
+		} else {
+			result, err = tx.Exec(`UPDATE labels SET name = ? WHERE kind = ? AND name = ?`, to, kind, name)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return 0, err
+		} else if n == 0 {
+			return 0, fmt.Errorf("%w: %s", ErrRecordNotFound, name)
+		}
+
+		result, err = tx.Exec(`UPDATE `+table+` SET `+column+` = ? WHERE `+column+` = ?`, to, name)
+		if err != nil {
+			return 0, err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		moved += n
+	}
+
+	dedupe := `
+		DELETE FROM ` + table + `
+		WHERE ` + column + ` = ? AND id NOT IN (
+			SELECT MIN(id) FROM ` + table + ` WHERE ` + column + ` = ? GROUP BY session_id
+		)
+	`
+	if _, err := tx.Exec(dedupe, to, to); err != nil {
+		return 0, err
+	}
+
+	return moved, tx.Commit()
+}
+
+// Delete removes a label from the catalog and from every session it was
+// used on, returning how many sessions lost it.
+func (m LabelModel) Delete(kind, name string) (int64, error) {
+	table, column, err := labelTable(kind)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM labels WHERE kind = ? AND name = ?`, kind, name)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, ErrRecordNotFound
+	}
+
+	var sessions int64
+	err = tx.QueryRow(`SELECT COUNT(DISTINCT session_id) FROM `+table+` WHERE `+column+` = ?`, name).Scan(&sessions)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM `+table+` WHERE `+column+` = ?`, name); err != nil {
+		return 0, err
+	}
+
+	return sessions, tx.Commit()
+}
+
+// Suggest returns catalog names close to name: the same apart from case, or
+// within two typos. Closest first.
+func Suggest(name string, catalog []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+
+	lower := strings.ToLower(name)
+	var candidates []candidate
+	for _, c := range catalog {
+		d := editDistance(lower, strings.ToLower(c))
+		if d <= 2 && d < len([]rune(name)) {
+			candidates = append(candidates, candidate{c, d})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		names[i] = c.name
+	}
+	return names
+}
+
+// editDistance is the Damerau–Levenshtein distance between a and b, counting
+// a swap of neighbouring letters as one typo.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
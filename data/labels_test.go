@@ -0,0 +1,111 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLabelRewrite(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      string
+		from      []string
+		to        string
+		merge     bool
+		wantMoved int64
+		want      map[int][]string
+		catalog   []string
+		wantErr   error
+	}{
+		{
+			name: "rename", kind: LabelTag, from: []string{"api"}, to: "backend-api",
+			wantMoved: 2,
+			want:      map[int][]string{0: {"backend-api", "ui"}, 1: {"backend-api", "go", "golang"}},
+			catalog:   []string{"backend-api", "go", "golang", "ui"},
+		},
+		{
+			name: "merge with dedupe", kind: LabelTag, from: []string{"golang"}, to: "go", merge: true,
+			wantMoved: 1,
+			want:      map[int][]string{0: {"api", "ui"}, 1: {"api", "go"}},
+			catalog:   []string{"api", "go", "ui"},
+		},
+		{
+			name: "merge several", kind: LabelTag, from: []string{"api", "ui"}, to: "go", merge: true,
+			wantMoved: 3,
+			want:      map[int][]string{0: {"go"}, 1: {"go", "golang"}},
+			catalog:   []string{"go", "golang"},
+		},
+		{
+			name: "rename kpi", kind: LabelKPI, from: []string{"speed"}, to: "velocity",
+			wantMoved: 1,
+			want:      map[int][]string{0: {"api", "ui"}, 1: {"api", "go", "golang"}},
+			catalog:   []string{"velocity"},
+		},
+		{name: "rename onto existing", kind: LabelTag, from: []string{"api"}, to: "ui", wantErr: ErrLabelExists},
+		{name: "merge into missing", kind: LabelTag, from: []string{"api"}, to: "rest", merge: true, wantErr: ErrRecordNotFound},
+		{name: "unknown label", kind: LabelTag, from: []string{"nope"}, to: "fresh", wantErr: ErrRecordNotFound},
+		{name: "unknown kind", kind: "colour", from: []string{"api"}, to: "fresh", wantErr: ErrUnknownLabel},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			m := NewModels(db)
+
+			task := seedTask(t, db, "Labels")
+			sessions := []*TaskSession{
+				seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"}),
+				seedSession(t, db, task, "a", span{"2025-03-03 11:00", "2025-03-03 12:00"}),
+			}
+			err := inTx(t, db, func(tx *sql.Tx) error {
+				if err := m.SessionTags.Create(tx, sessions[0].ID, []string{"api", "ui"}); err != nil {
+					return err
+				}
+				if err := m.SessionTags.Create(tx, sessions[1].ID, []string{"api", "go", "golang"}); err != nil {
+					return err
+				}
+				return m.SessionKPI.Create(tx, sessions[0].ID, []string{"speed"})
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			moved, err := m.Labels.rewrite(tt.kind, tt.from, tt.to, tt.merge)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if moved != tt.wantMoved {
+				t.Errorf("moved %d, want %d", moved, tt.wantMoved)
+			}
+
+			for i, want := range tt.want {
+				got, err := m.SessionTags.GetBySession(sessions[i].ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("session %d tags: got %v, want %v", i, got, want)
+				}
+			}
+
+			catalog, err := m.Labels.Names(tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sort.Strings(catalog)
+			if !reflect.DeepEqual(catalog, tt.catalog) {
+				t.Errorf("catalog: got %v, want %v", catalog, tt.catalog)
+			}
+		})
+	}
+}
@@ -17,12 +17,27 @@ type Log struct {
 type LogSession struct {
 	ID         int
 	Task       string
+	TaskStatus string
 	StartedAt  time.Time
 	EndedAt    time.Time
 	ActiveTime time.Duration
 	PausedTime time.Duration
 	TotalTime  time.Duration
 	Commits    []LogCommit
+	Notes      []LogNote
+
+	// Churn is the diff stats of all the session's commits together.
+	Churn DiffStat
+
+	// Estimate is the task's estimate, and TaskTime the active time logged
+	// on the task across all of its sessions.
+	Estimate time.Duration
+	TaskTime time.Duration
+}
+
+type LogNote struct {
+	Body    string
+	NotedAt time.Time
 }
 
 type LogCommit struct {
@@ -30,6 +45,7 @@ type LogCommit struct {
 	Hash    string
 	Author  string
 	Date    string
+	DiffStat
 }
 
 func (m LogModel) GetLogsWithDurations() ([]Log, error) {
@@ -42,35 +58,56 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 		WHERE end_time IS NOT NULL
 		GROUP BY session_id
 	),
+	task_totals AS (
+		SELECT
+			ts.task_id,
+			SUM(CAST(strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time) AS INTEGER)) AS task_seconds
+		FROM task_sessions ts
+		JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+		GROUP BY ts.task_id
+	),
 	session_logs AS (
 		SELECT
 			ts.id AS session_id,
 			t.description AS task_description,
+			t.status AS task_status,
 			ts.started_at,
 			ts.ended_at,
 			CAST(strftime('%s', COALESCE(ts.ended_at, CURRENT_TIMESTAMP)) - strftime('%s', ts.started_at) AS INTEGER) AS total_seconds,
 			IFNULL(it.active_seconds, 0) AS active_seconds,
+			IFNULL(t.estimate_seconds, 0) AS estimate_seconds,
+			IFNULL(tt.task_seconds, 0) AS task_seconds,
 			c.message AS commit_message,
 			c.hash AS commit_hash,
 			c.author AS commit_author,
-			c.date AS commit_date
+			c.date AS commit_date,
+			IFNULL(c.files_changed, 0) AS files_changed,
+			IFNULL(c.insertions, 0) AS insertions,
+			IFNULL(c.deletions, 0) AS deletions
 		FROM task_sessions ts
 		JOIN tasks t ON t.id = ts.task_id
 		LEFT JOIN interval_totals it ON it.session_id = ts.id
+		LEFT JOIN task_totals tt ON tt.task_id = ts.task_id
 		LEFT JOIN commits c ON c.session_id = ts.id
 	),
 	orphan_commits AS (
 		SELECT
 			NULL AS session_id,
 			'[Unassociated]' AS task_description,
+			'' AS task_status,
 			NULL AS started_at,
 			NULL AS ended_at,
 			0 AS total_seconds,
 			0 AS active_seconds,
+			0 AS estimate_seconds,
+			0 AS task_seconds,
 			c.message AS commit_message,
 			c.hash AS commit_hash,
 			c.author AS commit_author,
-			c.date AS commit_date
+			c.date AS commit_date,
+			IFNULL(c.files_changed, 0) AS files_changed,
+			IFNULL(c.insertions, 0) AS insertions,
+			IFNULL(c.deletions, 0) AS deletions
 		FROM commits c
 		WHERE c.session_id IS NULL
 	)
@@ -95,14 +132,18 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 	type rowData struct {
 		SessionID     sql.NullInt64
 		Task          string
+		TaskStatus    string
 		StartedAt     NullTime
 		EndedAt       NullTime
 		TotalSeconds  sql.NullInt64
 		ActiveSeconds int64
+		EstimateSecs  int64
+		TaskSeconds   int64
 		Message       sql.NullString
 		Hash          sql.NullString
 		Author        sql.NullString
 		CommitDate    sql.NullString
+		Stat          DiffStat
 	}
 
 	rowsData := []rowData{}
@@ -111,14 +152,20 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 		if err := rows.Scan(
 			&r.SessionID,
 			&r.Task,
+			&r.TaskStatus,
 			&r.StartedAt,
 			&r.EndedAt,
 			&r.TotalSeconds,
 			&r.ActiveSeconds,
+			&r.EstimateSecs,
+			&r.TaskSeconds,
 			&r.Message,
 			&r.Hash,
 			&r.Author,
 			&r.CommitDate,
+			&r.Stat.Files,
+			&r.Stat.Insertions,
+			&r.Stat.Deletions,
 		); err != nil {
 			return nil, err
 		}
@@ -141,11 +188,13 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 			}
 			if row.Message.Valid {
 				orphanSession.Commits = append(orphanSession.Commits, LogCommit{
-					Message: row.Message.String,
-					Hash:    row.Hash.String,
-					Author:  row.Author.String,
-					Date:    row.CommitDate.String,
+					Message:  row.Message.String,
+					Hash:     row.Hash.String,
+					Author:   row.Author.String,
+					Date:     row.CommitDate.String,
+					DiffStat: row.Stat,
 				})
+				orphanSession.Churn = orphanSession.Churn.Add(row.Stat)
 			}
 			continue
 		}
@@ -172,22 +221,38 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 			sessionsMap[sessionID] = &LogSession{
 				ID:         sessionID,
 				Task:       row.Task,
+				TaskStatus: row.TaskStatus,
 				StartedAt:  startedAt,
 				EndedAt:    endedAt,
 				TotalTime:  total,
 				ActiveTime: active,
 				PausedTime: paused,
+				Estimate:   time.Duration(row.EstimateSecs) * time.Second,
+				TaskTime:   time.Duration(row.TaskSeconds) * time.Second,
 			}
 			session = sessionsMap[sessionID]
 		}
 
 		if row.Message.Valid {
 			session.Commits = append(session.Commits, LogCommit{
-				Message: row.Message.String,
-				Hash:    row.Hash.String,
-				Author:  row.Author.String,
-				Date:    row.CommitDate.String,
+				Message:  row.Message.String,
+				Hash:     row.Hash.String,
+				Author:   row.Author.String,
+				Date:     row.CommitDate.String,
+				DiffStat: row.Stat,
 			})
+			session.Churn = session.Churn.Add(row.Stat)
+		}
+	}
+
+	notes, err := SessionNoteModel{m.DB}.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range notes {
+		if session, ok := sessionsMap[n.SessionID]; ok {
+			session.Notes = append(session.Notes, LogNote{Body: n.Body, NotedAt: n.NotedAt})
 		}
 	}
 
@@ -195,7 +260,7 @@ func (m LogModel) GetLogsWithDurations() ([]Log, error) {
 	logMap := make(map[string]*Log)
 
 	for _, session := range sessionsMap {
-		dateKey := session.StartedAt.Format("Jan 02")
+		dateKey := session.StartedAt.In(Location()).Format("Jan 02")
 		logDay, exists := logMap[dateKey]
 		if !exists {
 			logMap[dateKey] = &Log{
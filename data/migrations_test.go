@@ -0,0 +1,73 @@
+package data
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeTimestampsMigration(t *testing.T) {
+	db := NewSQLiteDB(filepath.Join(t.TempDir(), "db.sqlite"))
+	t.Cleanup(func() { db.Close() })
+	migrate(t, db, "000007")
+
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "already normalised", value: "2025-03-03 09:00:00", want: "2025-03-03 09:00:00"},
+		{name: "RFC 3339 UTC", value: "2025-03-03T09:00:00Z", want: "2025-03-03 09:00:00"},
+		{name: "positive offset", value: "2025-03-03 10:30:00+02:00", want: "2025-03-03 08:30:00"},
+		{name: "negative offset across midnight", value: "2025-03-03T21:15:00-05:00", want: "2025-03-04 02:15:00"},
+		{name: "fractional seconds", value: "2025-03-03 09:00:00.123456", want: "2025-03-03 09:00:00"},
+		{name: "driver format", value: "2025-03-03 09:00:00.5+01:00", want: "2025-03-03 08:00:00"},
+		{name: "unreadable", value: "yesterday", want: "yesterday"},
+		{name: "null", value: nil, want: nil},
+	}
+
+	if _, err := db.Exec(`INSERT INTO tasks (id, description) VALUES (1, 'Migrate')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO task_sessions (id, task_id, started_at) VALUES (1, 1, '2025-03-03 09:00:00')`); err != nil {
+		t.Fatal(err)
+	}
+	for i, tt := range tests {
+		_, err := db.Exec(`INSERT INTO task_session_intervals (id, session_id, start_time, end_time) VALUES (?, 1, '2025-03-03 08:00:00', ?)`,
+			i+1, tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	migration, err := os.ReadFile(filepath.Join("..", "migrations", "000007_normalize_timestamps.up.sql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Concatenating reads the stored text rather than the time the
+			// driver would parse it into.
+			var got sql.NullString
+			if err := db.QueryRow(`SELECT end_time || '' FROM task_session_intervals WHERE id = ?`, i+1).Scan(&got); err != nil {
+				t.Fatal(err)
+			}
+
+			switch want := tt.want.(type) {
+			case nil:
+				if got.Valid {
+					t.Fatalf("got %q, want NULL", got.String)
+				}
+			case string:
+				if got.String != want {
+					t.Fatalf("got %q, want %q", got.String, want)
+				}
+			}
+		})
+	}
+}
@@ -10,6 +10,13 @@ type Models struct {
 	TaskSessionIntervals TaskSessionIntervalModel
 	Commits              CommitModel
 	Logs                 LogModel
+	SessionEvents        SessionEventModel
+	Pomodoros            PomodoroModel
+	Projects             ProjectModel
+	SessionNotes         SessionNoteModel
+	Labels               LabelModel
+	Rates                RateModel
+	Invoices             InvoiceModel
 }
 
 func NewModels(DB *sql.DB) Models {
@@ -21,5 +28,12 @@ func NewModels(DB *sql.DB) Models {
 		SessionTags:          SessionTagModel{DB},
 		SessionKPI:           SessionKPIModel{DB},
 		Logs:                 LogModel{DB},
+		SessionEvents:        SessionEventModel{DB},
+		Pomodoros:            PomodoroModel{DB},
+		Projects:             ProjectModel{DB},
+		SessionNotes:         SessionNoteModel{DB},
+		Labels:               LabelModel{DB},
+		Rates:                RateModel{DB},
+		Invoices:             InvoiceModel{DB},
 	}
 }
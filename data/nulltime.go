@@ -19,15 +19,15 @@ func (nt *NullTime) Scan(value interface{}) error {
 
 	switch v := value.(type) {
 	case time.Time:
-		nt.Time = v
+		nt.Time = v.UTC()
 	case []byte:
-		parsed, err := time.Parse("2006-01-02 15:04:05", string(v))
+		parsed, err := parseStoredTime(string(v))
 		if err != nil {
 			return err
 		}
 		nt.Time = parsed
 	case string:
-		parsed, err := time.Parse("2006-01-02 15:04:05", v)
+		parsed, err := parseStoredTime(v)
 		if err != nil {
 			return err
 		}
@@ -44,5 +44,5 @@ func (nt NullTime) Value() (driver.Value, error) {
 	if !nt.Valid {
 		return nil, nil
 	}
-	return nt.Time.Format("2006-01-02 15:04:05"), nil
+	return formatTime(nt.Time), nil
 }
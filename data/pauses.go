@@ -0,0 +1,190 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	PauseMeeting   = "meeting"
+	PauseBreak     = "break"
+	PauseInterrupt = "interrupt"
+	PauseIdle      = "idle"
+	PauseOffHours  = "off_hours"
+
+	// PauseUnspecified labels pauses recorded without a reason.
+	PauseUnspecified = "unspecified"
+)
+
+// pauseGapsQuery lists the gaps between a session's intervals with the
+// reason stored on the interval before them. A paused active session's last
+// gap runs until now.
+const pauseGapsQuery = `
+	WITH ordered AS (
+		SELECT
+			tsi.session_id,
+			tsi.end_time AS gap_start,
+			LEAD(tsi.start_time) OVER (PARTITION BY tsi.session_id ORDER BY tsi.start_time) AS next_start,
+			COALESCE(NULLIF(TRIM(tsi.pause_reason), ''), 'unspecified') AS reason
+		FROM task_session_intervals tsi
+	),
+	gaps AS (
+		SELECT
+			o.session_id,
+			o.gap_start,
+			COALESCE(o.next_start, ts.ended_at, CURRENT_TIMESTAMP) AS gap_end,
+			o.reason
+		FROM ordered o
+		JOIN task_sessions ts ON ts.id = o.session_id
+		WHERE o.gap_start IS NOT NULL
+	)
+`
+
+type PauseTotal struct {
+	Reason string        `json:"reason"`
+	Paused time.Duration `json:"paused"`
+}
+
+type PauseStat struct {
+	Date   string        `json:"date"`
+	TaskID int           `json:"task_id"`
+	Task   string        `json:"task"`
+	Reason string        `json:"reason"`
+	Paused time.Duration `json:"paused"`
+}
+
+// SetPauseReason records why the session was paused after the interval.
+func (m TaskSessionIntervalModel) SetPauseReason(intervalID int, reason string) error {
+	query := `
+		UPDATE task_session_intervals
+		SET pause_reason = ?
+		WHERE id = ?
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, reason, intervalID)
+	return err
+}
+
+// SetPauseReasonTX is SetPauseReason inside tx.
+func (m TaskSessionIntervalModel) SetPauseReasonTX(tx *sql.Tx, intervalID int, reason string) error {
+	query := `
+		UPDATE task_session_intervals
+		SET pause_reason = ?
+		WHERE id = ?
+	`
+	_, err := tx.Exec(query, reason, intervalID)
+	return err
+}
+
+// PausesBySession returns the session's paused time per reason, longest
+// first.
+func (m TaskSessionIntervalModel) PausesBySession(sessionID int) ([]*PauseTotal, error) {
+	query := pauseGapsQuery + `
+		SELECT reason, SUM(strftime('%s', gap_end) - strftime('%s', gap_start)) AS seconds
+		FROM gaps
+		WHERE session_id = ?
+		GROUP BY reason
+		HAVING seconds > 0
+		ORDER BY seconds DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []*PauseTotal
+	for rows.Next() {
+		var (
+			total   PauseTotal
+			seconds int64
+		)
+		if err := rows.Scan(&total.Reason, &seconds); err != nil {
+			return nil, err
+		}
+		total.Paused = time.Duration(seconds) * time.Second
+		totals = append(totals, &total)
+	}
+
+	return totals, rows.Err()
+}
+
+// GetPauseBreakdown returns paused time per day, task and reason for the
+// last given number of days. Pauses are counted on the day they started in
+// the report timezone.
+func GetPauseBreakdown(db *sql.DB, days int) ([]*PauseStat, error) {
+	query := pauseGapsQuery + `
+		SELECT
+			g.gap_start,
+			t.id,
+			t.description,
+			g.reason,
+			strftime('%s', g.gap_end) - strftime('%s', g.gap_start) AS seconds
+		FROM gaps g
+		JOIN task_sessions ts ON ts.id = g.session_id
+		JOIN tasks t ON t.id = ts.task_id
+		WHERE g.gap_start >= ? AND seconds > 0
+		ORDER BY g.gap_start
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	since := startOfDay(time.Now()).AddDate(0, 0, -(days - 1))
+
+	rows, err := db.QueryContext(ctx, query, formatTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*PauseStat, 0)
+	index := make(map[string]*PauseStat)
+
+	for rows.Next() {
+		var (
+			gapStart NullTime
+			stat     PauseStat
+			seconds  int64
+		)
+		if err := rows.Scan(&gapStart, &stat.TaskID, &stat.Task, &stat.Reason, &seconds); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		stat.Date = dayKey(gapStart.Time)
+
+		key := fmt.Sprintf("%s|%d|%s", stat.Date, stat.TaskID, stat.Reason)
+		existing, ok := index[key]
+		if !ok {
+			existing = &stat
+			index[key] = existing
+			stats = append(stats, existing)
+		}
+		existing.Paused += time.Duration(seconds) * time.Second
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.Task != b.Task {
+			return a.Task < b.Task
+		}
+		return a.Paused > b.Paused
+	})
+
+	return stats, nil
+}
@@ -0,0 +1,196 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	PomodoroRunning   = "running"
+	PomodoroCompleted = "completed"
+	PomodoroAbandoned = "abandoned"
+)
+
+type PomodoroModel struct {
+	DB *sql.DB
+}
+
+type Pomodoro struct {
+	ID        int        `json:"id"`
+	SessionID int        `json:"session_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Status    string     `json:"status"`
+}
+
+type PomodoroDailyStat struct {
+	Date      string `json:"date"`
+	Completed int    `json:"completed"`
+	Abandoned int    `json:"abandoned"`
+}
+
+// SetTimer stores the focus and break lengths a session runs with.
+func (m PomodoroModel) SetTimer(sessionID int, focus, brk time.Duration) error {
+	query := `
+		UPDATE task_sessions
+		SET pomodoro_focus_seconds = ?, pomodoro_break_seconds = ?
+		WHERE id = ?
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, int64(focus.Seconds()), int64(brk.Seconds()), sessionID)
+	return err
+}
+
+func (m PomodoroModel) Start(sessionID int, at time.Time) (*Pomodoro, error) {
+	query := `
+		INSERT INTO pomodoros (session_id, started_at, status)
+		VALUES (?, ?, ?)
+		RETURNING id, session_id, started_at, status
+	`
+	var p Pomodoro
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, sessionID, formatTime(at), PomodoroRunning).Scan(
+		&p.ID,
+		&p.SessionID,
+		&p.StartedAt,
+		&p.Status,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// Finish closes a running pomodoro with the given status.
+func (m PomodoroModel) Finish(id int, at time.Time, status string) error {
+	query := `
+		UPDATE pomodoros
+		SET ended_at = ?, status = ?
+		WHERE id = ? AND status = 'running'
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, formatTime(at), status, id)
+	return err
+}
+
+// AbandonRunning marks any pomodoro still running on the session as
+// abandoned, e.g. when the session is paused or stopped by hand.
+func (m PomodoroModel) AbandonRunning(sessionID int, at time.Time) error {
+	query := `
+		UPDATE pomodoros
+		SET ended_at = ?, status = 'abandoned'
+		WHERE session_id = ? AND status = 'running'
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err := m.DB.ExecContext(ctx, query, formatTime(at), sessionID)
+	return err
+}
+
+// AbandonRunningTX is AbandonRunning inside tx.
+func (m PomodoroModel) AbandonRunningTX(tx *sql.Tx, sessionID int, at time.Time) error {
+	query := `
+		UPDATE pomodoros
+		SET ended_at = ?, status = 'abandoned'
+		WHERE session_id = ? AND status = 'running'
+	`
+	_, err := tx.Exec(query, formatTime(at), sessionID)
+	return err
+}
+
+// CountBySession returns how many of the session's pomodoros were completed
+// and how many were abandoned.
+func (m PomodoroModel) CountBySession(sessionID int) (completed, abandoned int, err error) {
+	query := `
+		SELECT
+			COALESCE(SUM(status = 'completed'), 0),
+			COALESCE(SUM(status = 'abandoned'), 0)
+		FROM pomodoros
+		WHERE session_id = ?
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err = m.DB.QueryRowContext(ctx, query, sessionID).Scan(&completed, &abandoned)
+	return completed, abandoned, err
+}
+
+// GetPomodoroStats returns completed and abandoned pomodoros per day for the
+// last seven days, in the report timezone. Days without pomodoros are left
+// out.
+func GetPomodoroStats(db *sql.DB) ([]*PomodoroDailyStat, error) {
+	query := `
+		SELECT started_at, status
+		FROM pomodoros
+		WHERE started_at >= ?
+		ORDER BY started_at
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	since := startOfDay(time.Now()).AddDate(0, 0, -6)
+
+	rows, err := db.QueryContext(ctx, query, formatTime(since))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	stats := make([]*PomodoroDailyStat, 0)
+	byDay := make(map[string]*PomodoroDailyStat)
+
+	for rows.Next() {
+		var (
+			startedAt time.Time
+			status    string
+		)
+		if err := rows.Scan(&startedAt, &status); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		day := dayKey(startedAt)
+		stat, ok := byDay[day]
+		if !ok {
+			stat = &PomodoroDailyStat{Date: day}
+			byDay[day] = stat
+			stats = append(stats, stat)
+		}
+
+		switch status {
+		case PomodoroCompleted:
+			stat.Completed++
+		case PomodoroAbandoned:
+			stat.Abandoned++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	return stats, nil
+}
+
+// MoveTX hands the pomodoros of one session that started at or after since
+// over to another session. A zero since moves all of them.
+func (m PomodoroModel) MoveTX(tx *sql.Tx, fromSessionID, toSessionID int, since time.Time) error {
+	query := `
+		UPDATE pomodoros
+		SET session_id = ?
+		WHERE session_id = ? AND started_at >= ?
+	`
+	_, err := tx.Exec(query, toSessionID, fromSessionID, formatTime(since))
+	return err
+}
@@ -0,0 +1,367 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ProjectModel struct {
+	DB *sql.DB
+}
+
+type Project struct {
+	ID        int
+	Name      string
+	Client    string
+	Remote    string
+	CreatedAt time.Time
+}
+
+// ProjectTotal is the time logged on a project's tasks. Tasks without a
+// project are totalled under ID 0.
+type ProjectTotal struct {
+	ID       int     `json:"id"`
+	Name     string  `json:"name"`
+	Client   string  `json:"client"`
+	Remote   string  `json:"remote"`
+	Tasks    int     `json:"tasks"`
+	Sessions int     `json:"sessions"`
+	Hours    float64 `json:"hours"`
+}
+
+// ParentTotal is the time logged on a task with subtasks, with and without
+// the subtasks below it.
+type ParentTotal struct {
+	ID       int     `json:"id"`
+	Task     string  `json:"task"`
+	Project  string  `json:"project"`
+	Subtasks int     `json:"subtasks"`
+	OwnHours float64 `json:"own_hours"`
+	Hours    float64 `json:"hours"`
+}
+
+// NormalizeRemote makes git remote URLs comparable: surrounding space, a
+// trailing slash and the .git suffix are dropped.
+func NormalizeRemote(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), "/")
+	return strings.TrimSuffix(remote, ".git")
+}
+
+func (m ProjectModel) Insert(p *Project) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := m.InsertTx(tx, p); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m ProjectModel) InsertTx(tx *sql.Tx, p *Project) error {
+	query := `
+		INSERT INTO projects (name, client, remote)
+		VALUES (?, ?, ?)
+		RETURNING id, created_at
+	`
+	p.Remote = NormalizeRemote(p.Remote)
+	return tx.QueryRow(query, p.Name, p.Client, p.Remote).Scan(&p.ID, &p.CreatedAt)
+}
+
+// GetByName returns the project with the given name, ignoring case, or
+// ErrRecordNotFound.
+func (m ProjectModel) GetByName(name string) (*Project, error) {
+	return m.getWhere(`name = ? COLLATE NOCASE`, strings.TrimSpace(name))
+}
+
+// GetByRemote returns the project tracking the given git remote, or
+// ErrRecordNotFound.
+func (m ProjectModel) GetByRemote(remote string) (*Project, error) {
+	remote = NormalizeRemote(remote)
+	if remote == "" {
+		return nil, ErrRecordNotFound
+	}
+	return m.getWhere(`remote = ?`, remote)
+}
+
+func (m ProjectModel) Get(id int) (*Project, error) {
+	return m.getWhere(`id = ?`, id)
+}
+
+func (m ProjectModel) getWhere(condition string, arg any) (*Project, error) {
+	query := `
+		SELECT id, name, client, remote, created_at
+		FROM projects
+		WHERE ` + condition + `
+		LIMIT 1
+	`
+	var p Project
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, arg).Scan(&p.ID, &p.Name, &p.Client, &p.Remote, &p.CreatedAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &p, nil
+}
+
+// Delete removes a project. Its tasks are kept without a project.
+func (m ProjectModel) Delete(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE tasks SET project_id = NULL WHERE project_id = ?`, id); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return ErrRecordNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetProjectTotals rolls the time of every task up to its project, busiest
+// project first. Tasks without a project come last, under ID 0.
+func GetProjectTotals(db *sql.DB) ([]*ProjectTotal, error) {
+	query := `
+		WITH totals AS (
+			SELECT
+				COALESCE(p.id, 0) AS id,
+				COALESCE(p.name, '') AS name,
+				COALESCE(p.client, '') AS client,
+				COALESCE(p.remote, '') AS remote,
+				COUNT(DISTINCT t.id) AS tasks,
+				COUNT(DISTINCT ts.id) AS sessions,
+				COALESCE(SUM(
+					strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+				), 0) AS seconds
+			FROM projects p
+			LEFT JOIN tasks t ON t.project_id = p.id
+			LEFT JOIN task_sessions ts ON ts.task_id = t.id
+			LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+			GROUP BY p.id
+
+			UNION ALL
+
+			SELECT
+				0, '', '', '',
+				COUNT(DISTINCT t.id),
+				COUNT(DISTINCT ts.id),
+				COALESCE(SUM(
+					strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+				), 0)
+			FROM tasks t
+			LEFT JOIN task_sessions ts ON ts.task_id = t.id
+			LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+			WHERE t.project_id IS NULL
+		)
+		SELECT id, name, client, remote, tasks, sessions, ROUND(seconds / 3600.0, 2)
+		FROM totals
+		WHERE id > 0 OR tasks > 0
+		ORDER BY id = 0, seconds DESC, name
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make([]*ProjectTotal, 0)
+	for rows.Next() {
+		var t ProjectTotal
+		if err := rows.Scan(&t.ID, &t.Name, &t.Client, &t.Remote, &t.Tasks, &t.Sessions, &t.Hours); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		totals = append(totals, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	if CurrentRounding() != nil {
+		pieces, err := loadPieces(ctx, db, time.Time{}, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		byProject := make(map[int][]piece)
+		for _, p := range pieces {
+			byProject[p.project] = append(byProject[p.project], p)
+		}
+		for _, t := range totals {
+			t.Hours = roundedHours(byProject[t.ID])
+		}
+
+		sort.SliceStable(totals, func(i, j int) bool {
+			if (totals[i].ID == 0) != (totals[j].ID == 0) {
+				return totals[j].ID == 0
+			}
+			return totals[i].Hours > totals[j].Hours
+		})
+	}
+
+	return totals, nil
+}
+
+// GetParentTotals rolls the time of every subtask, at any depth, up to each
+// task that has subtasks, busiest first.
+func GetParentTotals(db *sql.DB) ([]*ParentTotal, error) {
+	query := `
+		WITH RECURSIVE task_seconds AS (
+			SELECT
+				t.id,
+				COALESCE(SUM(
+					strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+				), 0) AS seconds
+			FROM tasks t
+			LEFT JOIN task_sessions ts ON ts.task_id = t.id
+			LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+			GROUP BY t.id
+		),
+		tree(root_id, id) AS (
+			SELECT id, id
+			FROM tasks
+			WHERE id IN (SELECT parent_id FROM tasks WHERE parent_id IS NOT NULL)
+			UNION
+			SELECT tree.root_id, t.id
+			FROM tasks t
+			JOIN tree ON t.parent_id = tree.id
+		)
+		SELECT
+			r.id,
+			r.description,
+			COALESCE(p.name, ''),
+			COUNT(*) - 1 AS subtasks,
+			ROUND(SUM(CASE WHEN tree.id = r.id THEN s.seconds ELSE 0 END) / 3600.0, 2) AS own_hours,
+			ROUND(SUM(s.seconds) / 3600.0, 2) AS hours
+		FROM tree
+		JOIN tasks r ON r.id = tree.root_id
+		LEFT JOIN projects p ON p.id = r.project_id
+		JOIN task_seconds s ON s.id = tree.id
+		GROUP BY r.id
+		ORDER BY hours DESC, r.id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	totals := make([]*ParentTotal, 0)
+	for rows.Next() {
+		var t ParentTotal
+		if err := rows.Scan(&t.ID, &t.Task, &t.Project, &t.Subtasks, &t.OwnHours, &t.Hours); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+		totals = append(totals, &t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	if CurrentRounding() != nil {
+		if err := roundParentTotals(ctx, db, totals); err != nil {
+			return nil, err
+		}
+	}
+
+	return totals, nil
+}
+
+// roundParentTotals works the hours of parent totals out again from the
+// intervals of each tree, with the configured rounding.
+func roundParentTotals(ctx context.Context, db *sql.DB, totals []*ParentTotal) error {
+	pieces, err := loadPieces(ctx, db, time.Time{}, time.Now())
+	if err != nil {
+		return err
+	}
+
+	byTask := make(map[int][]piece)
+	for _, p := range pieces {
+		byTask[p.task] = append(byTask[p.task], p)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		WITH RECURSIVE tree(root_id, id) AS (
+			SELECT id, id
+			FROM tasks
+			WHERE id IN (SELECT parent_id FROM tasks WHERE parent_id IS NOT NULL)
+			UNION
+			SELECT tree.root_id, t.id
+			FROM tasks t
+			JOIN tree ON t.parent_id = tree.id
+		)
+		SELECT root_id, id FROM tree
+	`)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	byRoot := make(map[int][]piece)
+	for rows.Next() {
+		var root, id int
+		if err := rows.Scan(&root, &id); err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		byRoot[root] = append(byRoot[root], byTask[id]...)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	for _, t := range totals {
+		t.OwnHours = roundedHours(byTask[t.ID])
+		t.Hours = roundedHours(byRoot[t.ID])
+	}
+
+	sort.SliceStable(totals, func(i, j int) bool {
+		return totals[i].Hours > totals[j].Hours
+	})
+
+	return nil
+}
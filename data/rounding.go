@@ -0,0 +1,257 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Which way reported time is rounded.
+const (
+	RoundUp      = "up"
+	RoundDown    = "down"
+	RoundNearest = "nearest"
+)
+
+// What is rounded on its own before times are added up.
+const (
+	RoundPerInterval = "interval"
+	RoundPerSession  = "session"
+	RoundPerDay      = "day"
+)
+
+// Rounding is how reports round logged time, e.g. every session up to the
+// next 15 minutes. Stored times are never rounded.
+type Rounding struct {
+	Step time.Duration
+	Mode string
+	Per  string
+}
+
+var (
+	roundingMu sync.RWMutex
+	rounding   *Rounding
+)
+
+// ParseRounding builds a rounding policy from its config values. An empty
+// mode rounds to the nearest step and an empty per rounds each session.
+func ParseRounding(step time.Duration, mode, per string) (*Rounding, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("rounding step must be more than zero, got %s", step)
+	}
+	if step%time.Second != 0 {
+		return nil, fmt.Errorf("rounding step must be whole seconds, got %s", step)
+	}
+
+	r := &Rounding{Step: step, Mode: strings.ToLower(strings.TrimSpace(mode)), Per: strings.ToLower(strings.TrimSpace(per))}
+	if r.Mode == "" {
+		r.Mode = RoundNearest
+	}
+	if r.Per == "" {
+		r.Per = RoundPerSession
+	}
+
+	switch r.Mode {
+	case RoundUp, RoundDown, RoundNearest:
+	default:
+		return nil, fmt.Errorf("unknown rounding mode %q (use up, down or nearest)", mode)
+	}
+
+	switch r.Per {
+	case RoundPerInterval, RoundPerSession, RoundPerDay:
+	default:
+		return nil, fmt.Errorf("unknown rounding unit %q (use interval, session or day)", per)
+	}
+
+	return r, nil
+}
+
+// SetRounding sets how reports round time. nil reports exact time.
+func SetRounding(r *Rounding) {
+	roundingMu.Lock()
+	defer roundingMu.Unlock()
+	rounding = r
+}
+
+// CurrentRounding returns the configured rounding, or nil if reports show
+// exact time.
+func CurrentRounding() *Rounding {
+	roundingMu.RLock()
+	defer roundingMu.RUnlock()
+	return rounding
+}
+
+// RoundingLabel describes the rounding reports apply, e.g.
+// "15m up per session", or "none".
+func RoundingLabel() string {
+	r := CurrentRounding()
+	if r == nil {
+		return "none"
+	}
+	return r.String()
+}
+
+func (r *Rounding) String() string {
+	step := r.Step.String()
+	if strings.HasSuffix(step, "m0s") {
+		step = strings.TrimSuffix(step, "0s")
+	}
+	if strings.HasSuffix(step, "h0m") {
+		step = strings.TrimSuffix(step, "0m")
+	}
+	return fmt.Sprintf("%s %s per %s", step, r.Mode, r.Per)
+}
+
+// Round rounds d to a multiple of the step.
+func (r *Rounding) Round(d time.Duration) time.Duration {
+	d = d.Truncate(time.Second)
+	if d <= 0 {
+		return 0
+	}
+
+	steps := float64(d) / float64(r.Step)
+	switch r.Mode {
+	case RoundUp:
+		steps = math.Ceil(steps)
+	case RoundDown:
+		steps = math.Floor(steps)
+	default:
+		steps = math.Floor(steps + 0.5)
+	}
+
+	return time.Duration(steps) * r.Step
+}
+
+// piece is the part of an interval that falls inside a report bucket.
+type piece struct {
+	interval   int
+	session    int
+	task       int
+	project    int
+	start, end time.Time
+}
+
+// loadPieces returns every interval overlapping [from, to), clipped to it,
+// or only those of sessionIDs when given. Open intervals run until now.
+func loadPieces(ctx context.Context, db *sql.DB, from, to time.Time, sessionIDs ...int) ([]piece, error) {
+	query := `
+		SELECT
+			tsi.id,
+			tsi.session_id,
+			ts.task_id,
+			COALESCE(t.project_id, 0),
+			tsi.start_time,
+			COALESCE(tsi.end_time, CURRENT_TIMESTAMP)
+		FROM task_session_intervals tsi
+		JOIN task_sessions ts ON ts.id = tsi.session_id
+		JOIN tasks t ON t.id = ts.task_id
+		WHERE
+			tsi.start_time < ? AND
+			COALESCE(tsi.end_time, CURRENT_TIMESTAMP) > ?
+	`
+	args := []any{formatTime(to), formatTime(from)}
+
+	if len(sessionIDs) > 0 {
+		query += ` AND tsi.session_id IN (?` + strings.Repeat(`, ?`, len(sessionIDs)-1) + `)`
+		for _, id := range sessionIDs {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var pieces []piece
+	for rows.Next() {
+		var (
+			p          piece
+			start, end string
+		)
+		if err := rows.Scan(&p.interval, &p.session, &p.task, &p.project, &start, &end); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		if p.start, err = parseStoredTime(start); err != nil {
+			return nil, err
+		}
+		if p.end, err = parseStoredTime(end); err != nil {
+			return nil, err
+		}
+
+		pieces = append(pieces, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	return clipPieces(pieces, from, to), nil
+}
+
+// clipPieces keeps the parts of pieces between from and to.
+func clipPieces(pieces []piece, from, to time.Time) []piece {
+	clipped := make([]piece, 0, len(pieces))
+	for _, p := range pieces {
+		if p.start.Before(from) {
+			p.start = from
+		}
+		if p.end.After(to) {
+			p.end = to
+		}
+		if p.end.After(p.start) {
+			clipped = append(clipped, p)
+		}
+	}
+	return clipped
+}
+
+// roundedTotal adds up pieces with the configured rounding: every interval,
+// session or day among them is rounded on its own first. Without rounding it
+// is the exact total.
+func roundedTotal(pieces []piece) time.Duration {
+	r := CurrentRounding()
+
+	var total time.Duration
+	if r == nil {
+		for _, p := range pieces {
+			total += p.end.Sub(p.start)
+		}
+		return total
+	}
+
+	groups := make(map[string]time.Duration)
+	for _, p := range pieces {
+		switch r.Per {
+		case RoundPerInterval:
+			groups[fmt.Sprintf("interval:%d", p.interval)] += p.end.Sub(p.start)
+		case RoundPerSession:
+			groups[fmt.Sprintf("session:%d", p.session)] += p.end.Sub(p.start)
+		default:
+			for start := p.start; start.Before(p.end); {
+				end := startOfDay(start).AddDate(0, 0, 1)
+				if end.After(p.end) {
+					end = p.end
+				}
+				groups["day:"+dayKey(start)] += end.Sub(start)
+				start = end
+			}
+		}
+	}
+
+	for _, d := range groups {
+		total += r.Round(d)
+	}
+	return total
+}
+
+// roundedHours is roundedTotal in hours, to two decimals.
+func roundedHours(pieces []piece) float64 {
+	return math.Round(roundedTotal(pieces).Hours()*100) / 100
+}
@@ -0,0 +1,108 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRounding(t *testing.T) {
+	tests := []struct {
+		name      string
+		step      time.Duration
+		mode, per string
+		want      string
+		wantErr   bool
+	}{
+		{name: "defaults", step: 15 * time.Minute, want: "15m nearest per session"},
+		{name: "explicit", step: 6 * time.Minute, mode: " Up ", per: "DAY", want: "6m up per day"},
+		{name: "hours", step: time.Hour, mode: "down", per: "interval", want: "1h down per interval"},
+		{name: "zero step", step: 0, wantErr: true},
+		{name: "fractional step", step: 1500 * time.Millisecond, wantErr: true},
+		{name: "unknown mode", step: time.Minute, mode: "sideways", wantErr: true},
+		{name: "unknown per", step: time.Minute, per: "week", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRounding(tt.step, tt.mode, tt.per)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want an error", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := r.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundingRound(t *testing.T) {
+	tests := []struct {
+		mode string
+		d    time.Duration
+		want time.Duration
+	}{
+		{RoundUp, 1 * time.Minute, 15 * time.Minute},
+		{RoundUp, 15 * time.Minute, 15 * time.Minute},
+		{RoundUp, 15*time.Minute + 500*time.Millisecond, 15 * time.Minute},
+		{RoundUp, 15*time.Minute + time.Second, 30 * time.Minute},
+		{RoundDown, 29 * time.Minute, 15 * time.Minute},
+		{RoundDown, 14 * time.Minute, 0},
+		{RoundNearest, 7 * time.Minute, 0},
+		{RoundNearest, 7*time.Minute + 30*time.Second, 15 * time.Minute},
+		{RoundNearest, 22 * time.Minute, 15 * time.Minute},
+		{RoundNearest, 23 * time.Minute, 30 * time.Minute},
+		{RoundUp, 0, 0},
+		{RoundUp, -time.Minute, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+" "+tt.d.String(), func(t *testing.T) {
+			r := &Rounding{Step: 15 * time.Minute, Mode: tt.mode, Per: RoundPerSession}
+			if got := r.Round(tt.d); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundedTotal(t *testing.T) {
+	SetLocation(time.UTC)
+
+	// Two sessions: one of 10m and 10m on the first day, one of 20m that
+	// runs past midnight.
+	pieces := []piece{
+		{interval: 1, session: 1, start: at(t, "2025-03-03 09:00"), end: at(t, "2025-03-03 09:10")},
+		{interval: 2, session: 1, start: at(t, "2025-03-03 10:00"), end: at(t, "2025-03-03 10:10")},
+		{interval: 3, session: 2, start: at(t, "2025-03-03 23:50"), end: at(t, "2025-03-04 00:10")},
+	}
+
+	tests := []struct {
+		name     string
+		rounding *Rounding
+		want     time.Duration
+	}{
+		{name: "exact", want: 40 * time.Minute},
+		{name: "up per interval", rounding: &Rounding{Step: 15 * time.Minute, Mode: RoundUp, Per: RoundPerInterval}, want: 60 * time.Minute},
+		{name: "up per session", rounding: &Rounding{Step: 15 * time.Minute, Mode: RoundUp, Per: RoundPerSession}, want: 60 * time.Minute},
+		{name: "down per session", rounding: &Rounding{Step: 15 * time.Minute, Mode: RoundDown, Per: RoundPerSession}, want: 30 * time.Minute},
+		{name: "up per day", rounding: &Rounding{Step: 15 * time.Minute, Mode: RoundUp, Per: RoundPerDay}, want: 45 * time.Minute},
+		{name: "nearest per day", rounding: &Rounding{Step: 15 * time.Minute, Mode: RoundNearest, Per: RoundPerDay}, want: 45 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetRounding(tt.rounding)
+			t.Cleanup(func() { SetRounding(nil) })
+
+			if got := roundedTotal(pieces); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
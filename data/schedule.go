@@ -0,0 +1,251 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Schedule describes the working hours: the weekdays worked, the clock times
+// the day starts and ends and an optional lunch break. Clock times are
+// offsets from midnight in the report timezone.
+type Schedule struct {
+	Days       map[time.Weekday]bool
+	Start      time.Duration
+	End        time.Duration
+	LunchStart time.Duration
+	LunchEnd   time.Duration
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+var (
+	scheduleMu sync.RWMutex
+	schedule   *Schedule
+)
+
+// ParseSchedule builds a schedule from its config values. days holds
+// weekday names ("mon", "Tuesday"), start and end are clock times ("09:00")
+// and lunch is an optional range ("12:30-13:30"). Without days, Monday to
+// Friday are worked.
+func ParseSchedule(days []string, start, end, lunch string) (*Schedule, error) {
+	s := &Schedule{Days: make(map[time.Weekday]bool)}
+
+	if len(days) == 0 {
+		days = []string{"mon", "tue", "wed", "thu", "fri"}
+	}
+	for _, name := range days {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if len(key) > 3 {
+			key = key[:3]
+		}
+		day, ok := weekdayNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown weekday %q", name)
+		}
+		s.Days[day] = true
+	}
+
+	var err error
+	if s.Start, err = parseClock(start); err != nil {
+		return nil, fmt.Errorf("start: %w", err)
+	}
+	if s.End, err = parseClock(end); err != nil {
+		return nil, fmt.Errorf("end: %w", err)
+	}
+	if s.End <= s.Start {
+		return nil, fmt.Errorf("end %s must be after start %s", end, start)
+	}
+
+	if lunch = strings.TrimSpace(lunch); lunch != "" {
+		from, to, ok := strings.Cut(lunch, "-")
+		if !ok {
+			return nil, fmt.Errorf("lunch %q must look like 12:30-13:30", lunch)
+		}
+		if s.LunchStart, err = parseClock(from); err != nil {
+			return nil, fmt.Errorf("lunch: %w", err)
+		}
+		if s.LunchEnd, err = parseClock(to); err != nil {
+			return nil, fmt.Errorf("lunch: %w", err)
+		}
+		if s.LunchEnd <= s.LunchStart || s.LunchStart < s.Start || s.LunchEnd > s.End {
+			return nil, fmt.Errorf("lunch %q must fall within the working day", lunch)
+		}
+	}
+
+	return s, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a clock time like 09:00", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// SetSchedule sets the working hours overtime is measured against. A nil
+// schedule turns overtime off.
+func SetSchedule(s *Schedule) {
+	scheduleMu.Lock()
+	defer scheduleMu.Unlock()
+	schedule = s
+}
+
+// CurrentSchedule returns the configured working hours, or nil if there are
+// none.
+func CurrentSchedule() *Schedule {
+	scheduleMu.RLock()
+	defer scheduleMu.RUnlock()
+	return schedule
+}
+
+// clockOn returns the instant an offset from midnight falls on for the day
+// of t in the report timezone.
+func clockOn(t time.Time, offset time.Duration) time.Time {
+	y, m, d := t.In(Location()).Date()
+	return time.Date(y, m, d, int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, Location())
+}
+
+// Windows returns the scheduled working periods on the day of t, in order.
+func (s *Schedule) Windows(t time.Time) [][2]time.Time {
+	if !s.Days[t.In(Location()).Weekday()] {
+		return nil
+	}
+
+	if s.LunchEnd == 0 {
+		return [][2]time.Time{{clockOn(t, s.Start), clockOn(t, s.End)}}
+	}
+
+	return [][2]time.Time{
+		{clockOn(t, s.Start), clockOn(t, s.LunchStart)},
+		{clockOn(t, s.LunchEnd), clockOn(t, s.End)},
+	}
+}
+
+// InHours reports whether t falls within scheduled working hours.
+func (s *Schedule) InHours(t time.Time) bool {
+	for _, w := range s.Windows(t) {
+		if !t.Before(w[0]) && t.Before(w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// LastDayEnd returns the end of the latest working day that ended at or
+// before t, looking back at most a week.
+func (s *Schedule) LastDayEnd(t time.Time) (time.Time, bool) {
+	for i := 0; i < 8; i++ {
+		day := startOfDay(t).AddDate(0, 0, -i)
+		if !s.Days[day.Weekday()] {
+			continue
+		}
+		if end := clockOn(day, s.End); !end.After(t) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Scheduled returns how much of the span from start to end falls within
+// scheduled working hours.
+func (s *Schedule) Scheduled(start, end time.Time) time.Duration {
+	var total time.Duration
+	for day := startOfDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, w := range s.Windows(day) {
+			from, to := w[0], w[1]
+			if start.After(from) {
+				from = start
+			}
+			if end.Before(to) {
+				to = end
+			}
+			if to.After(from) {
+				total += to.Sub(from)
+			}
+		}
+	}
+	return total
+}
+
+// Overtime returns how much of the span from start to end falls outside
+// scheduled working hours.
+func (s *Schedule) Overtime(start, end time.Time) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start) - s.Scheduled(start, end)
+}
+
+// overtimeHours returns the hours worked outside the schedule in each
+// period. Without a schedule there is no overtime.
+func overtimeHours(ctx context.Context, db *sql.DB, periods []period) ([]float64, error) {
+	hours := make([]float64, len(periods))
+
+	s := CurrentSchedule()
+	if s == nil || len(periods) == 0 {
+		return hours, nil
+	}
+
+	query := `
+		SELECT start_time, end_time
+		FROM task_session_intervals
+		WHERE
+			start_time < ? AND
+			COALESCE(end_time, CURRENT_TIMESTAMP) > ?
+	`
+
+	from, to := periods[0].start, periods[len(periods)-1].end
+
+	rows, err := db.QueryContext(ctx, query, formatTime(to), formatTime(from))
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+
+	for rows.Next() {
+		var (
+			start time.Time
+			end   NullTime
+		)
+		if err := rows.Scan(&start, &end); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		stop := now
+		if end.Valid {
+			stop = end.Time
+		}
+
+		for i, p := range periods {
+			from, to := start, stop
+			if p.start.After(from) {
+				from = p.start
+			}
+			if p.end.Before(to) {
+				to = p.end
+			}
+			hours[i] += s.Overtime(from, to).Hours()
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,142 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+const (
+	EventAutoPause  = "auto_pause"
+	EventAutoResume = "auto_resume"
+	EventStaleCap   = "stale_cap"
+
+	EventScheduleStop  = "schedule_stop"
+	EventSchedulePause = "schedule_pause"
+)
+
+type SessionEventModel struct {
+	DB *sql.DB
+}
+
+type SessionEvent struct {
+	ID         int       `json:"id"`
+	SessionID  int       `json:"session_id"`
+	Kind       string    `json:"kind"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Detail     string    `json:"detail"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (m SessionEventModel) Create(e *SessionEvent) error {
+	query := `
+		INSERT INTO session_events (session_id, kind, occurred_at, detail)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+	args := []any{e.SessionID, e.Kind, formatTime(e.OccurredAt), e.Detail}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, args...).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (m SessionEventModel) CreateTX(tx *sql.Tx, e *SessionEvent) error {
+	query := `
+		INSERT INTO session_events (session_id, kind, occurred_at, detail)
+		VALUES (?, ?, ?, ?)
+		RETURNING id, created_at
+	`
+	args := []any{e.SessionID, e.Kind, formatTime(e.OccurredAt), e.Detail}
+
+	return tx.QueryRow(query, args...).Scan(&e.ID, &e.CreatedAt)
+}
+
+// Latest returns the most recent event recorded for the session, or nil if
+// there is none.
+func (m SessionEventModel) Latest(sessionID int) (*SessionEvent, error) {
+	query := `
+		SELECT id, session_id, kind, occurred_at, COALESCE(detail, ''), created_at
+		FROM session_events
+		WHERE session_id = ?
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT 1
+	`
+	var e SessionEvent
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, sessionID).Scan(
+		&e.ID,
+		&e.SessionID,
+		&e.Kind,
+		&e.OccurredAt,
+		&e.Detail,
+		&e.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return &e, nil
+}
+
+// GetAll lists events newest first. A sessionID of 0 returns events for
+// every session.
+func (m SessionEventModel) GetAll(sessionID int, limit int) ([]*SessionEvent, error) {
+	query := `
+		SELECT id, session_id, kind, occurred_at, COALESCE(detail, ''), created_at
+		FROM session_events
+		WHERE ? = 0 OR session_id = ?
+		ORDER BY occurred_at DESC, id DESC
+		LIMIT ?
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, sessionID, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*SessionEvent
+	for rows.Next() {
+		var e SessionEvent
+		err := rows.Scan(
+			&e.ID,
+			&e.SessionID,
+			&e.Kind,
+			&e.OccurredAt,
+			&e.Detail,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+
+	return events, rows.Err()
+}
+
+// MoveTX hands the events of one session that occurred at or after since
+// over to another session. A zero since moves all of them.
+func (m SessionEventModel) MoveTX(tx *sql.Tx, fromSessionID, toSessionID int, since time.Time) error {
+	query := `
+		UPDATE session_events
+		SET session_id = ?
+		WHERE session_id = ? AND occurred_at >= ?
+	`
+	_, err := tx.Exec(query, toSessionID, fromSessionID, formatTime(since))
+	return err
+}
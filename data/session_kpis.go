@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"time"
 )
@@ -27,5 +28,43 @@ func (m SessionKPIModel) Create(tx *sql.Tx, sessionID int, kpis []string) error
 			return err
 		}
 	}
-	return nil
+	return registerLabelsTx(tx, LabelKPI, kpis)
+}
+
+func (m SessionKPIModel) GetBySession(sessionID int) ([]string, error) {
+	query := `
+		SELECT kpi
+		FROM session_kpis
+		WHERE session_id = ?
+		ORDER BY id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var kpis []string
+	for rows.Next() {
+		var kpi string
+		if err := rows.Scan(&kpi); err != nil {
+			return nil, err
+		}
+		kpis = append(kpis, kpi)
+	}
+
+	return kpis, rows.Err()
+}
+
+// Update replaces every kpi on the session with the given list.
+func (m SessionKPIModel) Update(tx *sql.Tx, sessionID int, kpis []string) error {
+	if _, err := tx.Exec(`DELETE FROM session_kpis WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+
+	return m.Create(tx, sessionID, kpis)
 }
@@ -0,0 +1,83 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SessionNoteModel struct {
+	DB *sql.DB
+}
+
+// SessionNote is a timestamped journal entry on a session.
+type SessionNote struct {
+	ID        int       `json:"id"`
+	SessionID int       `json:"session_id"`
+	Body      string    `json:"body"`
+	NotedAt   time.Time `json:"noted_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (m SessionNoteModel) Create(n *SessionNote) error {
+	query := `
+		INSERT INTO session_notes (session_id, body, noted_at)
+		VALUES (?, ?, ?)
+		RETURNING id, created_at
+	`
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, n.SessionID, n.Body, formatTime(n.NotedAt)).Scan(&n.ID, &n.CreatedAt)
+}
+
+// GetBySession lists the session's notes oldest first.
+func (m SessionNoteModel) GetBySession(sessionID int) ([]*SessionNote, error) {
+	return m.query(`WHERE session_id = ?`, sessionID)
+}
+
+// GetAll lists every note, by session and then oldest first.
+func (m SessionNoteModel) GetAll() ([]*SessionNote, error) {
+	return m.query(``)
+}
+
+func (m SessionNoteModel) query(where string, args ...any) ([]*SessionNote, error) {
+	query := `
+		SELECT id, session_id, body, noted_at, created_at
+		FROM session_notes
+		` + where + `
+		ORDER BY session_id, noted_at, id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := make([]*SessionNote, 0)
+	for rows.Next() {
+		var n SessionNote
+		if err := rows.Scan(&n.ID, &n.SessionID, &n.Body, &n.NotedAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notes = append(notes, &n)
+	}
+
+	return notes, rows.Err()
+}
+
+// MoveTX hands the notes of one session taken at or after since over to
+// another session. A zero since moves all of them.
+func (m SessionNoteModel) MoveTX(tx *sql.Tx, fromSessionID, toSessionID int, since time.Time) error {
+	query := `
+		UPDATE session_notes
+		SET session_id = ?
+		WHERE session_id = ? AND noted_at >= ?
+	`
+	_, err := tx.Exec(query, toSessionID, fromSessionID, formatTime(since))
+	return err
+}
@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"time"
 )
@@ -27,5 +28,43 @@ func (m SessionTagModel) Create(tx *sql.Tx, sessionID int, tags []string) error
 			return err
 		}
 	}
-	return nil
+	return registerLabelsTx(tx, LabelTag, tags)
+}
+
+func (m SessionTagModel) GetBySession(sessionID int) ([]string, error) {
+	query := `
+		SELECT tag
+		FROM session_tags
+		WHERE session_id = ?
+		ORDER BY id
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// Update replaces every tag on the session with the given list.
+func (m SessionTagModel) Update(tx *sql.Tx, sessionID int, tags []string) error {
+	if _, err := tx.Exec(`DELETE FROM session_tags WHERE session_id = ?`, sessionID); err != nil {
+		return err
+	}
+
+	return m.Create(tx, sessionID, tags)
 }
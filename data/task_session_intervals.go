@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 )
 
@@ -12,21 +14,132 @@ type TaskSessionIntervalModel struct {
 }
 
 type TaskSessionInterval struct {
-	ID        int
-	SessionID int
-	StartTime time.Time
-	EndTime   *time.Time
+	ID          int
+	SessionID   int
+	StartTime   time.Time
+	EndTime     *time.Time
+	PauseReason string
 }
 
-func (m TaskSessionIntervalModel) CreateTX(tx *sql.Tx, sessionID int) error {
+func (m TaskSessionIntervalModel) CreateTX(tx *sql.Tx, sessionID int, start time.Time) error {
 	query := `
-		INSERT INTO task_session_intervals (session_id)
-		VALUES (?)
+		INSERT INTO task_session_intervals (session_id, start_time)
+		VALUES (?, ?)
 	`
-	_, err := tx.Exec(query, sessionID)
+	_, err := tx.Exec(query, sessionID, formatTime(start))
 	return err
 }
 
+func (m TaskSessionIntervalModel) CreateClosedTX(tx *sql.Tx, tsi *TaskSessionInterval) error {
+	query := `
+		INSERT INTO task_session_intervals (session_id, start_time, end_time, pause_reason)
+		VALUES (?, ?, ?, NULLIF(?, ''))
+		RETURNING id
+	`
+	args := []any{tsi.SessionID, formatTime(tsi.StartTime), formatTime(*tsi.EndTime), tsi.PauseReason}
+
+	return tx.QueryRow(query, args...).Scan(&tsi.ID)
+}
+
+// OverlapsTX reports whether [start, end) overlaps any stored interval in
+// the same workspace. Intervals belonging to excludeSessionID are ignored,
+// and open intervals are treated as running until now. Sessions without a
+// workspace are checked against every workspace.
+func (m TaskSessionIntervalModel) OverlapsTX(tx *sql.Tx, workspace string, excludeSessionID int, start, end time.Time) (bool, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM task_session_intervals tsi
+		JOIN task_sessions ts ON ts.id = tsi.session_id
+		WHERE tsi.session_id != ?
+			AND (? = '' OR ts.workspace IN (?, ''))
+			AND tsi.start_time < ?
+			AND COALESCE(tsi.end_time, CURRENT_TIMESTAMP) > ?
+	`
+	var count int
+
+	args := []any{excludeSessionID, workspace, workspace, formatTime(end), formatTime(start)}
+
+	err := tx.QueryRow(query, args...).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// ValidateIntervals checks that every interval is closed, ends after it
+// starts, lies within the session bounds and does not overlap its siblings.
+// The intervals are sorted by start time in place.
+func ValidateIntervals(sessionStart, sessionEnd time.Time, intervals []*TaskSessionInterval) error {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].StartTime.Before(intervals[j].StartTime)
+	})
+
+	for i, tsi := range intervals {
+		if tsi.EndTime == nil {
+			return fmt.Errorf("interval starting %s has no end time", tsi.StartTime.Format(timeLayout))
+		}
+		if !tsi.EndTime.After(tsi.StartTime) {
+			return ErrInvalidInterval
+		}
+		if tsi.StartTime.Before(sessionStart) || tsi.EndTime.After(sessionEnd) {
+			return fmt.Errorf("interval %s - %s falls outside the session",
+				tsi.StartTime.Format(timeLayout), tsi.EndTime.Format(timeLayout))
+		}
+		if i > 0 && tsi.StartTime.Before(*intervals[i-1].EndTime) {
+			return ErrOverlappingInterval
+		}
+	}
+
+	return nil
+}
+
+// InsertPause cuts the pause [start, end) out of the interval it falls in,
+// for a pause that wasn't recorded at the time. A pause in the middle of an
+// interval splits it; the second part is appended with no ID and takes over
+// the interval's end and pause reason. Open intervals run until now.
+func InsertPause(intervals []*TaskSessionInterval, start, end time.Time) ([]*TaskSessionInterval, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidInterval
+	}
+
+	now := time.Now()
+	for _, tsi := range intervals {
+		tsiEnd := now
+		if tsi.EndTime != nil {
+			tsiEnd = *tsi.EndTime
+		}
+		if start.Before(tsi.StartTime) || end.After(tsiEnd) {
+			continue
+		}
+
+		switch {
+		case start.Equal(tsi.StartTime) && end.Equal(tsiEnd):
+			return nil, fmt.Errorf("the pause covers all of interval #%d", tsi.ID)
+		case start.Equal(tsi.StartTime):
+			tsi.StartTime = end
+		case end.Equal(tsiEnd) && tsi.EndTime != nil:
+			tsi.EndTime = &start
+			tsi.PauseReason = ""
+		default:
+			rest := &TaskSessionInterval{
+				SessionID:   tsi.SessionID,
+				StartTime:   end,
+				EndTime:     tsi.EndTime,
+				PauseReason: tsi.PauseReason,
+			}
+			tsi.EndTime = &start
+			tsi.PauseReason = ""
+			intervals = append(intervals, rest)
+		}
+
+		return intervals, nil
+	}
+
+	return nil, fmt.Errorf("the pause %s - %s doesn't fall inside one interval",
+		start.In(Location()).Format(timeLayout), end.In(Location()).Format(timeLayout))
+}
+
 func (m TaskSessionIntervalModel) Create(sessionID int) error {
 	query := `
 		INSERT INTO task_session_intervals (session_id)
@@ -39,11 +152,14 @@ func (m TaskSessionIntervalModel) Create(sessionID int) error {
 	return err
 }
 
-func (m TaskSessionIntervalModel) End(ts *TaskSession) (*TaskSessionInterval, error) {
+// End closes the session's open interval at the given time. It returns nil
+// if no interval is open and ErrBeforeActivity if at is before the interval
+// started.
+func (m TaskSessionIntervalModel) End(ts *TaskSession, at time.Time) (*TaskSessionInterval, error) {
 	query := `
 		UPDATE task_session_intervals
-		SET end_time = CURRENT_TIMESTAMP
-		WHERE session_id = ? AND end_time IS NULL
+		SET end_time = ?
+		WHERE session_id = ? AND end_time IS NULL AND start_time <= ?
 		RETURNING id, session_id, start_time, end_time
 	`
 	var tsi TaskSessionInterval
@@ -51,7 +167,9 @@ func (m TaskSessionIntervalModel) End(ts *TaskSession) (*TaskSessionInterval, er
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, ts.ID).Scan(
+	stamp := formatTime(at)
+
+	err := m.DB.QueryRowContext(ctx, query, stamp, ts.ID, stamp).Scan(
 		&tsi.ID,
 		&tsi.SessionID,
 		&tsi.StartTime,
@@ -61,6 +179,13 @@ func (m TaskSessionIntervalModel) End(ts *TaskSession) (*TaskSessionInterval, er
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
+			open, err := m.HasOpenInterval(ts.ID)
+			if err != nil {
+				return nil, err
+			}
+			if open {
+				return nil, ErrBeforeActivity
+			}
 			return nil, nil
 		default:
 			return nil, err
@@ -69,6 +194,33 @@ func (m TaskSessionIntervalModel) End(ts *TaskSession) (*TaskSessionInterval, er
 	return &tsi, nil
 }
 
+// GetOpen returns the session's running interval, or nil if it is paused.
+func (m TaskSessionIntervalModel) GetOpen(sessionID int) (*TaskSessionInterval, error) {
+	query := `
+		SELECT id, session_id, start_time
+		FROM task_session_intervals
+		WHERE session_id = ? AND end_time IS NULL
+		ORDER BY start_time DESC
+		LIMIT 1
+	`
+	var tsi TaskSessionInterval
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, sessionID).Scan(&tsi.ID, &tsi.SessionID, &tsi.StartTime)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return &tsi, nil
+}
+
 func (m TaskSessionIntervalModel) HasOpenInterval(sessionID int) (bool, error) {
 	query := `
 		SELECT COUNT(*) FROM task_session_intervals
@@ -87,19 +239,153 @@ func (m TaskSessionIntervalModel) HasOpenInterval(sessionID int) (bool, error) {
 	return count > 0, nil
 }
 
-func (m TaskSessionIntervalModel) StartNew(sessionID int) (*TaskSessionInterval, error) {
+// StartNew opens a new interval at the given time. The time may not be
+// before the session started, and the new interval may not overlap any
+// interval recorded in the session's workspace.
+func (m TaskSessionIntervalModel) StartNew(sessionID int, at time.Time) (*TaskSessionInterval, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	stamp := formatTime(at)
+
+	check := `
+		SELECT
+			(SELECT started_at > ? FROM task_sessions WHERE id = ?),
+			(
+				SELECT COUNT(*)
+				FROM task_session_intervals tsi
+				JOIN task_sessions ts ON ts.id = tsi.session_id
+				JOIN task_sessions cur ON cur.id = ?
+				WHERE (cur.workspace = '' OR ts.workspace IN (cur.workspace, ''))
+					AND tsi.start_time < ?
+					AND COALESCE(tsi.end_time, CURRENT_TIMESTAMP) > ?
+			)
+	`
+	var (
+		beforeStart sql.NullBool
+		overlaps    int
+	)
+
+	args := []any{stamp, sessionID, sessionID, formatTime(time.Now()), stamp}
+
+	err := m.DB.QueryRowContext(ctx, check, args...).Scan(&beforeStart, &overlaps)
+	if err != nil {
+		return nil, err
+	}
+
+	if !beforeStart.Valid {
+		return nil, ErrRecordNotFound
+	}
+	if beforeStart.Bool {
+		return nil, ErrBeforeActivity
+	}
+	if overlaps > 0 {
+		return nil, ErrOverlappingInterval
+	}
+
 	query := `
 		INSERT INTO task_session_intervals (session_id, start_time)
-		VALUES (?, CURRENT_TIMESTAMP)
+		VALUES (?, ?)
 		RETURNING id, session_id, start_time, end_time
 	`
 
 	var tsi TaskSessionInterval
 
+	err = m.DB.QueryRowContext(ctx, query, sessionID, stamp).Scan(
+		&tsi.ID,
+		&tsi.SessionID,
+		&tsi.StartTime,
+		&tsi.EndTime,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tsi, nil
+}
+
+func (m TaskSessionIntervalModel) GetAllForSession(sessionID int) ([]*TaskSessionInterval, error) {
+	query := `
+		SELECT id, session_id, start_time, end_time, COALESCE(pause_reason, '')
+		FROM task_session_intervals
+		WHERE session_id = ?
+		ORDER BY start_time
+	`
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, sessionID).Scan(
+	rows, err := m.DB.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var intervals []*TaskSessionInterval
+
+	for rows.Next() {
+		var tsi TaskSessionInterval
+		var endTime sql.NullTime
+
+		if err := rows.Scan(&tsi.ID, &tsi.SessionID, &tsi.StartTime, &endTime, &tsi.PauseReason); err != nil {
+			return nil, err
+		}
+
+		if endTime.Valid {
+			tsi.EndTime = &endTime.Time
+		}
+
+		intervals = append(intervals, &tsi)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return intervals, nil
+}
+
+func (m TaskSessionIntervalModel) UpdateTX(tx *sql.Tx, tsi *TaskSessionInterval) error {
+	query := `
+		UPDATE task_session_intervals
+		SET start_time = ?, end_time = ?, pause_reason = NULLIF(?, '')
+		WHERE id = ? AND session_id = ?
+	`
+
+	var endTime any
+	if tsi.EndTime != nil {
+		endTime = formatTime(*tsi.EndTime)
+	}
+
+	result, err := tx.Exec(query, formatTime(tsi.StartTime), endTime, tsi.PauseReason, tsi.ID, tsi.SessionID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// EndTX closes the session's open interval at the given time inside tx. It
+// returns nil if no interval was open.
+func (m TaskSessionIntervalModel) EndTX(tx *sql.Tx, sessionID int, at time.Time) (*TaskSessionInterval, error) {
+	query := `
+		UPDATE task_session_intervals
+		SET end_time = ?
+		WHERE session_id = ? AND end_time IS NULL
+		RETURNING id, session_id, start_time, end_time
+	`
+	var tsi TaskSessionInterval
+
+	err := tx.QueryRow(query, formatTime(at), sessionID).Scan(
 		&tsi.ID,
 		&tsi.SessionID,
 		&tsi.StartTime,
@@ -107,9 +393,24 @@ func (m TaskSessionIntervalModel) StartNew(sessionID int) (*TaskSessionInterval,
 	)
 
 	if err != nil {
-		return nil, err
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, err
+		}
 	}
-
 	return &tsi, nil
 }
 
+// MoveTX hands the intervals of one session that start at or after since
+// over to another session. A zero since moves all of them.
+func (m TaskSessionIntervalModel) MoveTX(tx *sql.Tx, fromSessionID, toSessionID int, since time.Time) error {
+	query := `
+		UPDATE task_session_intervals
+		SET session_id = ?
+		WHERE session_id = ? AND start_time >= ?
+	`
+	_, err := tx.Exec(query, toSessionID, fromSessionID, formatTime(since))
+	return err
+}
@@ -0,0 +1,223 @@
+package data
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateIntervals(t *testing.T) {
+	tests := []struct {
+		name    string
+		spans   []span
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "ordered", spans: []span{{"2025-03-03 09:00", "2025-03-03 12:00"}, {"2025-03-03 13:00", "2025-03-03 17:00"}}},
+		{name: "unordered", spans: []span{{"2025-03-03 13:00", "2025-03-03 17:00"}, {"2025-03-03 09:00", "2025-03-03 12:00"}}},
+		{name: "touching", spans: []span{{"2025-03-03 09:00", "2025-03-03 12:00"}, {"2025-03-03 12:00", "2025-03-03 17:00"}}},
+		{name: "open", spans: []span{{"2025-03-03 09:00", ""}}, anyErr: true},
+		{name: "empty", spans: []span{{"2025-03-03 10:00", "2025-03-03 10:00"}}, wantErr: ErrInvalidInterval},
+		{name: "backwards", spans: []span{{"2025-03-03 11:00", "2025-03-03 10:00"}}, wantErr: ErrInvalidInterval},
+		{name: "before the session", spans: []span{{"2025-03-03 08:30", "2025-03-03 10:00"}}, anyErr: true},
+		{name: "after the session", spans: []span{{"2025-03-03 16:00", "2025-03-03 17:30"}}, anyErr: true},
+		{name: "overlapping", spans: []span{{"2025-03-03 09:00", "2025-03-03 12:00"}, {"2025-03-03 11:00", "2025-03-03 13:00"}}, wantErr: ErrOverlappingInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals := make([]*TaskSessionInterval, len(tt.spans))
+			for i, s := range tt.spans {
+				intervals[i] = &TaskSessionInterval{StartTime: at(t, s.start)}
+				if s.end != "" {
+					end := at(t, s.end)
+					intervals[i].EndTime = &end
+				}
+			}
+
+			err := ValidateIntervals(at(t, "2025-03-03 09:00"), at(t, "2025-03-03 17:00"), intervals)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("got no error")
+				}
+			case err != nil:
+				t.Fatalf("got %v", err)
+			}
+
+			for i := 1; i < len(intervals); i++ {
+				if intervals[i].StartTime.Before(intervals[i-1].StartTime) {
+					t.Fatal("intervals were not sorted")
+				}
+			}
+		})
+	}
+}
+
+func TestInsertPause(t *testing.T) {
+	tests := []struct {
+		name    string
+		pause   span
+		want    []string
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "in the middle", pause: span{"2025-03-03 10:00", "2025-03-03 10:30"}, want: []string{"09:00-10:00", "13:00-…", "10:30-12:00"}},
+		{name: "at the start", pause: span{"2025-03-03 09:00", "2025-03-03 09:30"}, want: []string{"09:30-12:00", "13:00-…"}},
+		{name: "at the end", pause: span{"2025-03-03 11:30", "2025-03-03 12:00"}, want: []string{"09:00-11:30", "13:00-…"}},
+		{name: "in the open interval", pause: span{"2025-03-03 14:00", "2025-03-03 14:30"}, want: []string{"09:00-12:00", "13:00-14:00", "14:30-…"}},
+		{name: "whole interval", pause: span{"2025-03-03 09:00", "2025-03-03 12:00"}, anyErr: true},
+		{name: "across intervals", pause: span{"2025-03-03 11:30", "2025-03-03 13:30"}, anyErr: true},
+		{name: "in a gap", pause: span{"2025-03-03 12:15", "2025-03-03 12:45"}, anyErr: true},
+		{name: "backwards", pause: span{"2025-03-03 10:30", "2025-03-03 10:00"}, wantErr: ErrInvalidInterval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := at(t, "2025-03-03 12:00")
+			intervals := []*TaskSessionInterval{
+				{ID: 1, StartTime: at(t, "2025-03-03 09:00"), EndTime: &end, PauseReason: PauseMeeting},
+				{ID: 2, StartTime: at(t, "2025-03-03 13:00")},
+			}
+
+			got, err := InsertPause(intervals, at(t, tt.pause.start), at(t, tt.pause.end))
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			case tt.anyErr:
+				if err == nil {
+					t.Fatal("got no error")
+				}
+				return
+			case err != nil:
+				t.Fatalf("got %v", err)
+			}
+
+			bounds := make([]string, len(got))
+			for i, tsi := range got {
+				end := "…"
+				if tsi.EndTime != nil {
+					end = tsi.EndTime.Format("15:04")
+				}
+				bounds[i] = tsi.StartTime.Format("15:04") + "-" + end
+			}
+			if !reflect.DeepEqual(bounds, tt.want) {
+				t.Fatalf("got %v, want %v", bounds, tt.want)
+			}
+
+			if tt.name == "in the middle" {
+				if got[0].PauseReason != "" || got[2].PauseReason != PauseMeeting || got[2].ID != 0 {
+					t.Fatalf("the pause reason should move to the new part: %+v %+v", got[0], got[2])
+				}
+			}
+		})
+	}
+}
+
+func TestOverlapsTX(t *testing.T) {
+	db := newTestDB(t)
+	task := seedTask(t, db, "Overlaps")
+
+	first := seedSession(t, db, task, "a",
+		span{"2025-03-03 09:00", "2025-03-03 10:00"},
+		span{"2025-03-03 11:00", "2025-03-03 12:00"})
+	seedSession(t, db, task, "b", span{"2025-03-03 09:00", "2025-03-03 12:00"})
+	seedSession(t, db, task, "", span{"2025-03-03 13:00", "2025-03-03 14:00"})
+	seedSession(t, db, task, "c", span{"2025-03-04 09:00", ""})
+
+	tests := []struct {
+		name      string
+		workspace string
+		exclude   int
+		interval  span
+		want      bool
+	}{
+		{name: "in a gap", workspace: "a", interval: span{"2025-03-03 10:00", "2025-03-03 11:00"}},
+		{name: "overlapping", workspace: "a", interval: span{"2025-03-03 09:30", "2025-03-03 10:30"}, want: true},
+		{name: "own session excluded", workspace: "a", exclude: first.ID, interval: span{"2025-03-03 09:30", "2025-03-03 10:30"}},
+		{name: "other workspace", workspace: "d", interval: span{"2025-03-03 09:30", "2025-03-03 10:30"}},
+		{name: "session without workspace", workspace: "d", interval: span{"2025-03-03 13:30", "2025-03-03 13:45"}, want: true},
+		{name: "no workspace checks all", interval: span{"2025-03-03 09:30", "2025-03-03 09:45"}, want: true},
+		{name: "open interval runs until now", workspace: "c", interval: span{"2025-06-01 09:00", "2025-06-01 10:00"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			err := inTx(t, db, func(tx *sql.Tx) error {
+				var err error
+				got, err = TaskSessionIntervalModel{db}.OverlapsTX(tx, tt.workspace, tt.exclude, at(t, tt.interval.start), at(t, tt.interval.end))
+				return err
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartNew(t *testing.T) {
+	tests := []struct {
+		name string
+		// other is a session running in another workspace since then, or
+		// in the same one with sameWorkspace.
+		other         string
+		sameWorkspace bool
+		resume        string
+		wantErr       error
+	}{
+		{name: "after the last interval", resume: "2025-03-03 10:30"},
+		{name: "other workspace running", other: "2025-03-03 08:00", resume: "2025-03-03 10:30"},
+		{name: "same workspace running", other: "2025-03-03 11:00", sameWorkspace: true, resume: "2025-03-03 10:30", wantErr: ErrOverlappingInterval},
+		{name: "inside the last interval", resume: "2025-03-03 09:30", wantErr: ErrOverlappingInterval},
+		{name: "before the session", resume: "2025-03-03 08:00", wantErr: ErrBeforeActivity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			task := seedTask(t, db, "Resume")
+
+			paused := seedSession(t, db, task, "a", span{"2025-03-03 09:00", "2025-03-03 10:00"})
+			if _, err := db.Exec(`UPDATE task_sessions SET ended_at = NULL WHERE id = ?`, paused.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.other != "" {
+				workspace := "b"
+				if tt.sameWorkspace {
+					workspace = "a"
+				}
+				seedSession(t, db, task, workspace, span{tt.other, ""})
+			}
+
+			tsi, err := TaskSessionIntervalModel{db}.StartNew(paused.ID, at(t, tt.resume))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !tsi.StartTime.Equal(at(t, tt.resume)) {
+				t.Fatalf("interval starts %v, want %s", tsi.StartTime, tt.resume)
+			}
+		})
+	}
+}
+
+func TestStartNewUnknownSession(t *testing.T) {
+	db := newTestDB(t)
+
+	if _, err := (TaskSessionIntervalModel{db}).StartNew(42, time.Now()); !errors.Is(err, ErrRecordNotFound) {
+		t.Fatalf("got %v, want %v", err, ErrRecordNotFound)
+	}
+}
@@ -8,7 +8,10 @@ import (
 )
 
 var (
-	ErrRecordNotFound = errors.New("record not found")
+	ErrRecordNotFound      = errors.New("record not found")
+	ErrOverlappingInterval = errors.New("interval overlaps an existing interval")
+	ErrInvalidInterval     = errors.New("interval must end after it starts")
+	ErrBeforeActivity      = errors.New("time is before activity already recorded in this session")
 )
 
 type TaskSessionModel struct {
@@ -23,6 +26,8 @@ type TaskSession struct {
 	Mode      string
 	Notes     string
 	Synced    bool
+	Workspace string
+	Billable  bool
 	Tags      []string
 	KPIs      []string
 }
@@ -34,15 +39,23 @@ type DetailedTaskSession struct {
 
 func (m TaskSessionModel) CreateTX(tx *sql.Tx, ts *TaskSession) (*TaskSession, error) {
 	query := `
-		INSERT INTO task_sessions (task_id, mode, notes, synced)
-		VALUES (?, ?, ?, ?)
+		INSERT INTO task_sessions (task_id, started_at, mode, notes, synced, workspace)
+		VALUES (?, COALESCE(?, CURRENT_TIMESTAMP), ?, ?, ?, ?)
 		RETURNING id, started_at
 	`
+
+	var startedAt any
+	if !ts.StartedAt.IsZero() {
+		startedAt = formatTime(ts.StartedAt)
+	}
+
 	args := []any{
 		ts.TaskID,
+		startedAt,
 		ts.Mode,
 		ts.Notes,
 		ts.Synced,
+		ts.Workspace,
 	}
 
 	err := tx.QueryRow(query, args...).Scan(&ts.ID, &ts.StartedAt)
@@ -53,6 +66,34 @@ func (m TaskSessionModel) CreateTX(tx *sql.Tx, ts *TaskSession) (*TaskSession, e
 	return ts, nil
 }
 
+func (m TaskSessionModel) CreateClosedTX(tx *sql.Tx, ts *TaskSession) (*TaskSession, error) {
+	if ts.EndedAt == nil {
+		return nil, errors.New("closed session requires an end time")
+	}
+
+	query := `
+		INSERT INTO task_sessions (task_id, started_at, ended_at, mode, notes, synced, workspace)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+	args := []any{
+		ts.TaskID,
+		formatTime(ts.StartedAt),
+		formatTime(*ts.EndedAt),
+		ts.Mode,
+		ts.Notes,
+		ts.Synced,
+		ts.Workspace,
+	}
+
+	err := tx.QueryRow(query, args...).Scan(&ts.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
 func (m TaskSessionModel) GetAllWithTask() ([]*DetailedTaskSession, error) {
 	query := `
 		SELECT 
@@ -120,7 +161,12 @@ func (m TaskSessionModel) GetByID(sessionID int) (*TaskSession, error) {
 			id,
 			task_id,
 			started_at,
-			ended_at
+			ended_at,
+			COALESCE(mode, 'personal'),
+			COALESCE(notes, ''),
+			COALESCE(synced, 0),
+			workspace,
+			billable
 		FROM task_sessions
 		WHERE id = ?
 	`
@@ -136,6 +182,11 @@ func (m TaskSessionModel) GetByID(sessionID int) (*TaskSession, error) {
 		&ts.TaskID,
 		&ts.StartedAt,
 		&endedAt,
+		&ts.Mode,
+		&ts.Notes,
+		&ts.Synced,
+		&ts.Workspace,
+		&ts.Billable,
 	)
 	if err != nil {
 		switch {
@@ -155,11 +206,16 @@ func (m TaskSessionModel) GetByID(sessionID int) (*TaskSession, error) {
 	return &ts, nil
 }
 
-func (m TaskSessionModel) Get() (*TaskSession, error) {
+// Get returns the active session of a workspace. Sessions recorded before
+// workspaces existed have an empty workspace and count as active everywhere;
+// an empty workspace matches any session.
+func (m TaskSessionModel) Get(workspace string) (*TaskSession, error) {
 	query := `
-		SELECT ts.id
+		SELECT ts.id, ts.workspace
 		FROM task_sessions ts
 		WHERE ts.ended_at IS NULL
+			AND (? = '' OR ts.workspace IN (?, ''))
+		ORDER BY ts.workspace DESC
 		LIMIT 1
 	`
 	var ts TaskSession
@@ -167,7 +223,7 @@ func (m TaskSessionModel) Get() (*TaskSession, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query).Scan(&ts.ID)
+	err := m.DB.QueryRowContext(ctx, query, workspace, workspace).Scan(&ts.ID, &ts.Workspace)
 	if err != nil {
 		switch {
 		// No active task session found
@@ -181,11 +237,19 @@ func (m TaskSessionModel) Get() (*TaskSession, error) {
 	return &ts, nil
 }
 
-func (m TaskSessionModel) Stop(sessionID int) (*TaskSession, error) {
+// Stop ends the session at the given time. It returns nil if the session
+// was already stopped and ErrBeforeActivity if at falls before the session
+// start or before any of its intervals.
+func (m TaskSessionModel) Stop(sessionID int, at time.Time) (*TaskSession, error) {
 	query := `
 		UPDATE task_sessions
-		SET ended_at = CURRENT_TIMESTAMP
+		SET ended_at = ?
 		WHERE id = ? AND ended_at IS NULL
+			AND started_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM task_session_intervals
+				WHERE session_id = ? AND COALESCE(end_time, start_time) > ?
+			)
 		RETURNING id, task_id, started_at, ended_at
 	`
 
@@ -194,7 +258,9 @@ func (m TaskSessionModel) Stop(sessionID int) (*TaskSession, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, sessionID).Scan(
+	stamp := formatTime(at)
+
+	err := m.DB.QueryRowContext(ctx, query, stamp, sessionID, stamp, sessionID, stamp).Scan(
 		&ts.ID,
 		&ts.TaskID,
 		&ts.StartedAt,
@@ -204,6 +270,13 @@ func (m TaskSessionModel) Stop(sessionID int) (*TaskSession, error) {
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
+			current, err := m.GetByID(sessionID)
+			if err != nil {
+				return nil, err
+			}
+			if current.EndedAt == nil {
+				return nil, ErrBeforeActivity
+			}
 			return nil, nil
 		default:
 			return nil, err
@@ -212,3 +285,114 @@ func (m TaskSessionModel) Stop(sessionID int) (*TaskSession, error) {
 
 	return &ts, nil
 }
+
+func (m TaskSessionModel) UpdateTX(tx *sql.Tx, ts *TaskSession) error {
+	query := `
+		UPDATE task_sessions
+		SET task_id = ?, started_at = ?, ended_at = ?, mode = ?, notes = ?
+		WHERE id = ?
+	`
+
+	var endedAt any
+	if ts.EndedAt != nil {
+		endedAt = formatTime(*ts.EndedAt)
+	}
+
+	args := []any{
+		ts.TaskID,
+		formatTime(ts.StartedAt),
+		endedAt,
+		ts.Mode,
+		ts.Notes,
+		ts.ID,
+	}
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SetBillableTX marks whether the session's time can be invoiced. Sessions
+// are billable unless marked otherwise.
+func (m TaskSessionModel) SetBillableTX(tx *sql.Tx, sessionID int, billable bool) error {
+	_, err := tx.Exec(`UPDATE task_sessions SET billable = ? WHERE id = ?`, billable, sessionID)
+	return err
+}
+
+// MoveOpenTX moves the sessions still running in one workspace to another
+// inside tx, as when a project claims a repository's remote.
+func (m TaskSessionModel) MoveOpenTX(tx *sql.Tx, from, to string) error {
+	_, err := tx.Exec(`UPDATE task_sessions SET workspace = ? WHERE workspace = ? AND ended_at IS NULL`, to, from)
+	return err
+}
+
+// StopTX ends the session at the given time inside tx. It returns nil if the
+// session was already stopped.
+func (m TaskSessionModel) StopTX(tx *sql.Tx, sessionID int, at time.Time) (*TaskSession, error) {
+	query := `
+		UPDATE task_sessions
+		SET ended_at = ?
+		WHERE id = ? AND ended_at IS NULL
+		RETURNING id, task_id, started_at, ended_at
+	`
+
+	var ts TaskSession
+
+	err := tx.QueryRow(query, formatTime(at), sessionID).Scan(
+		&ts.ID,
+		&ts.TaskID,
+		&ts.StartedAt,
+		&ts.EndedAt,
+	)
+
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return &ts, nil
+}
+
+// DeleteTX removes a session together with its tags, KPIs and notes.
+// Intervals and commits have to be moved or removed by the caller first.
+func (m TaskSessionModel) DeleteTX(tx *sql.Tx, sessionID int) error {
+	for _, query := range []string{
+		`DELETE FROM session_tags WHERE session_id = ?`,
+		`DELETE FROM session_kpis WHERE session_id = ?`,
+		`DELETE FROM session_notes WHERE session_id = ?`,
+	} {
+		if _, err := tx.Exec(query, sessionID); err != nil {
+			return err
+		}
+	}
+
+	result, err := tx.Exec(`DELETE FROM task_sessions WHERE id = ?`, sessionID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -1,7 +1,11 @@
 package data
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -12,16 +16,320 @@ type TaskModel struct {
 type Task struct {
 	ID          int
 	Description string
+	Estimate    time.Duration
+	ProjectID   int
+	ParentID    int
+	Status      string
+	Priority    int
+	CompletedAt *time.Time
 	CreatedAt   time.Time
 }
 
+type TaskSummary struct {
+	Task
+	Project    string
+	Sessions   int
+	ActiveTime time.Duration
+	LastWorked *time.Time
+}
+
 func (m TaskModel) CreateTx(tx *sql.Tx, task *Task) error {
 	query := `
-		INSERT INTO tasks (description)
-		VALUES (?)
+		INSERT INTO tasks (description, estimate_seconds, project_id, parent_id, status, priority)
+		VALUES (?, NULLIF(?, 0), NULLIF(?, 0), NULLIF(?, 0), ?, ?)
 		RETURNING id, created_at
 	`
-	return tx.QueryRow(query, task.Description).Scan(&task.ID, &task.CreatedAt)
+	if task.Status == "" {
+		task.Status = TaskTodo
+	}
+	if task.Priority == 0 {
+		task.Priority = PriorityMedium
+	}
+
+	args := []any{task.Description, int64(task.Estimate.Seconds()), task.ProjectID, task.ParentID, task.Status, task.Priority}
+	return tx.QueryRow(query, args...).Scan(&task.ID, &task.CreatedAt)
+}
+
+// UpdateTx saves the task's description.
+func (m TaskModel) UpdateTx(tx *sql.Tx, task *Task) error {
+	query := `
+		UPDATE tasks
+		SET description = ?
+		WHERE id = ?
+	`
+	_, err := tx.Exec(query, task.Description, task.ID)
+	return err
+}
+
+// UpdatePlanTx saves the task's estimate, project and parent. Zero values
+// clear them.
+func (m TaskModel) UpdatePlanTx(tx *sql.Tx, task *Task) error {
+	query := `
+		UPDATE tasks
+		SET
+			estimate_seconds = NULLIF(?, 0),
+			project_id = NULLIF(?, 0),
+			parent_id = NULLIF(?, 0)
+		WHERE id = ?
+	`
+	_, err := tx.Exec(query, int64(task.Estimate.Seconds()), task.ProjectID, task.ParentID, task.ID)
+	return err
+}
+
+// InAncestryTx reports whether ancestorID is taskID itself or one of the
+// tasks above it.
+func (m TaskModel) InAncestryTx(tx *sql.Tx, taskID, ancestorID int) (bool, error) {
+	query := `
+		WITH RECURSIVE chain(id) AS (
+			SELECT ?
+			UNION
+			SELECT t.parent_id
+			FROM tasks t
+			JOIN chain c ON t.id = c.id
+			WHERE t.parent_id IS NOT NULL
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = ?)
+	`
+	var found bool
+	err := tx.QueryRow(query, taskID, ancestorID).Scan(&found)
+	return found, err
 }
 
+const taskColumns = `id, description, COALESCE(estimate_seconds, 0), COALESCE(project_id, 0), COALESCE(parent_id, 0),
+	status, priority, completed_at, created_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (*Task, error) {
+	var (
+		task        Task
+		estimate    int64
+		completedAt NullTime
+	)
+	err := row.Scan(
+		&task.ID,
+		&task.Description,
+		&estimate,
+		&task.ProjectID,
+		&task.ParentID,
+		&task.Status,
+		&task.Priority,
+		&completedAt,
+		&task.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	task.Estimate = time.Duration(estimate) * time.Second
+	if completedAt.Valid {
+		task.CompletedAt = &completedAt.Time
+	}
+	return &task, nil
+}
+
+func (m TaskModel) Get(id int) (*Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = ?
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	task, err := scanTask(m.DB.QueryRowContext(ctx, query, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return task, nil
+}
+
+func (m TaskModel) GetAll() ([]*Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		ORDER BY created_at DESC, id DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
+	var tasks []*Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, rows.Err()
+}
+
+// Search returns the tasks whose description resembles query, best match
+// first. An exact (case-insensitive) match always ranks on top.
+func (m TaskModel) Search(query string) ([]*Task, error) {
+	tasks, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	type scored struct {
+		task  *Task
+		score int
+	}
+
+	var matches []scored
+	for _, task := range tasks {
+		if score := matchScore(query, task.Description); score > 0 {
+			matches = append(matches, scored{task, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]*Task, len(matches))
+	for i, match := range matches {
+		result[i] = match.task
+	}
+
+	return result, nil
+}
+
+// GetAllWithTotals lists every task with its cumulative active time across
+// all of its sessions. Running intervals count up to now.
+func (m TaskModel) GetAllWithTotals() ([]*TaskSummary, error) {
+	query := `
+		SELECT
+			t.id,
+			t.description,
+			t.status,
+			t.priority,
+			COALESCE(t.estimate_seconds, 0),
+			t.created_at,
+			COALESCE(p.name, '') AS project,
+			COUNT(DISTINCT ts.id) AS sessions,
+			COALESCE(SUM(
+				strftime('%s', COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) - strftime('%s', tsi.start_time)
+			), 0) AS active_seconds,
+			MAX(COALESCE(tsi.end_time, CURRENT_TIMESTAMP)) AS last_worked
+		FROM tasks t
+		LEFT JOIN projects p ON p.id = t.project_id
+		LEFT JOIN task_sessions ts ON ts.task_id = t.id
+		LEFT JOIN task_session_intervals tsi ON tsi.session_id = ts.id
+		GROUP BY t.id
+		ORDER BY last_worked IS NULL, last_worked DESC, t.id DESC
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var summaries []*TaskSummary
+	for rows.Next() {
+		var (
+			ts              TaskSummary
+			estimateSeconds int64
+			activeSeconds   int64
+			lastWorked      NullTime
+		)
+
+		err := rows.Scan(
+			&ts.ID,
+			&ts.Description,
+			&ts.Status,
+			&ts.Priority,
+			&estimateSeconds,
+			&ts.CreatedAt,
+			&ts.Project,
+			&ts.Sessions,
+			&activeSeconds,
+			&lastWorked,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ts.Estimate = time.Duration(estimateSeconds) * time.Second
+		ts.ActiveTime = time.Duration(activeSeconds) * time.Second
+		if lastWorked.Valid {
+			ts.LastWorked = &lastWorked.Time
+		}
+
+		summaries = append(summaries, &ts)
+	}
+
+	return summaries, rows.Err()
+}
+
+// matchScore rates how closely candidate resembles query, from 0 (no match)
+// to 100 (same text ignoring case and spacing).
+func matchScore(query, candidate string) int {
+	q := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	c := strings.Join(strings.Fields(strings.ToLower(candidate)), " ")
+
+	if q == "" || c == "" {
+		return 0
+	}
+
+	switch {
+	case q == c:
+		return 100
+	case strings.Contains(c, q), strings.Contains(q, c):
+		return 75
+	}
+
+	queryWords := strings.Fields(q)
+	candidateWords := make(map[string]bool)
+	for _, w := range strings.Fields(c) {
+		candidateWords[w] = true
+	}
+
+	shared := 0
+	for _, w := range queryWords {
+		if candidateWords[w] {
+			shared++
+		}
+	}
+	if shared > 0 {
+		return 20 + 50*shared/len(queryWords)
+	}
+
+	if isSubsequence(q, c) {
+		return 10
+	}
+
+	return 0
+}
+
+func isSubsequence(needle, haystack string) bool {
+	runes := []rune(needle)
+	i := 0
+	for _, r := range haystack {
+		if i < len(runes) && runes[i] == r {
+			i++
+		}
+	}
+	return i == len(runes)
+}
@@ -0,0 +1,86 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// timeLayout matches the format SQLite uses for CURRENT_TIMESTAMP, so
+// explicit timestamps compare correctly against column defaults. Every
+// instant is stored in UTC in this format.
+const timeLayout = "2006-01-02 15:04:05"
+
+// storedTimeLayouts are accepted when reading timestamps back. Older rows
+// may carry fractional seconds or an offset written by the driver.
+var storedTimeLayouts = []string{
+	timeLayout,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.RFC3339Nano,
+}
+
+var (
+	locationMu sync.RWMutex
+	location   = time.Local
+)
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// parseStoredTime reads a timestamp from the database. Values without an
+// offset are UTC.
+func parseStoredTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range storedTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognised timestamp %q", value)
+}
+
+// SetLocation sets the timezone that days, weeks and months are reported in
+// and times are displayed in. It defaults to the system timezone.
+func SetLocation(loc *time.Location) {
+	locationMu.Lock()
+	defer locationMu.Unlock()
+	location = loc
+}
+
+// Location returns the report and display timezone.
+func Location() *time.Location {
+	locationMu.RLock()
+	defer locationMu.RUnlock()
+	return location
+}
+
+// startOfDay returns midnight of t's day in the report timezone.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.In(Location()).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, Location())
+}
+
+// startOfWeek returns midnight of the Monday of t's week in the report
+// timezone.
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// startOfMonth returns midnight of the first of t's month in the report
+// timezone.
+func startOfMonth(t time.Time) time.Time {
+	y, m, _ := t.In(Location()).Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, Location())
+}
+
+// dayKey names the day t falls on in the report timezone.
+func dayKey(t time.Time) string {
+	return t.In(Location()).Format("2006-01-02")
+}
@@ -0,0 +1,81 @@
+// Package invoice renders invoices with templates the user can override.
+package invoice
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/tormgibbs/worklogger/data"
+)
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+var Formats = []string{"md", "html", "csv"}
+
+// templateData is what a template sees: the invoice, plus the last day
+// billed since invoice periods end exclusively.
+type templateData struct {
+	*data.Invoice
+	Through time.Time
+}
+
+var funcs = map[string]any{
+	"date": func(t time.Time) string {
+		return t.In(data.Location()).Format("2006-01-02")
+	},
+	"money": func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	},
+	"hours": func(v float64) string {
+		return fmt.Sprintf("%.2f", v)
+	},
+	"csv": func(s string) string {
+		if strings.ContainsAny(s, "\",\n\r") {
+			return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+		}
+		return s
+	},
+}
+
+// Render writes inv in format to w. A file named invoice.<format>.tmpl in
+// dir replaces the built-in template for that format.
+func Render(w io.Writer, format string, inv *data.Invoice, dir string) error {
+	name := "invoice." + format + ".tmpl"
+
+	src, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, os.ErrNotExist) || dir == "" {
+		src, err = defaults.ReadFile("templates/" + name)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unknown invoice format %q (use %s)", format, strings.Join(Formats, ", "))
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+
+	td := templateData{Invoice: inv, Through: inv.To.AddDate(0, 0, -1)}
+
+	// HTML gets escaped; the other formats are plain text.
+	if format == "html" {
+		t, err := htmltemplate.New(name).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		return t.Execute(w, td)
+	}
+
+	t, err := texttemplate.New(name).Funcs(funcs).Parse(string(src))
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return t.Execute(w, td)
+}
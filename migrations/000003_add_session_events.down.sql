@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_session_events_session_id;
+
+DROP TABLE IF EXISTS session_events;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_pomodoros_started_at;
+DROP INDEX IF EXISTS idx_pomodoros_session_id;
+
+DROP TABLE IF EXISTS pomodoros;
+
+ALTER TABLE task_sessions DROP COLUMN pomodoro_break_seconds;
+ALTER TABLE task_sessions DROP COLUMN pomodoro_focus_seconds;
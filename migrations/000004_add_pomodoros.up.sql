@@ -0,0 +1,14 @@
+ALTER TABLE task_sessions ADD COLUMN pomodoro_focus_seconds INTEGER;
+ALTER TABLE task_sessions ADD COLUMN pomodoro_break_seconds INTEGER;
+
+CREATE TABLE IF NOT EXISTS pomodoros (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  session_id INTEGER NOT NULL,
+  started_at DATETIME NOT NULL,
+  ended_at DATETIME,
+  status TEXT NOT NULL DEFAULT 'running',
+  FOREIGN KEY (session_id) REFERENCES task_sessions(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_pomodoros_session_id ON pomodoros(session_id);
+CREATE INDEX IF NOT EXISTS idx_pomodoros_started_at ON pomodoros(started_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_task_sessions_workspace;
+
+ALTER TABLE task_sessions DROP COLUMN workspace;
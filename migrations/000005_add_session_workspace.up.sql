@@ -0,0 +1,3 @@
+ALTER TABLE task_sessions ADD COLUMN workspace TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_task_sessions_workspace ON task_sessions(workspace, ended_at);
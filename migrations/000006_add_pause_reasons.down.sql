@@ -0,0 +1 @@
+ALTER TABLE task_session_intervals DROP COLUMN pause_reason;
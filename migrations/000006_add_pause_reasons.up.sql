@@ -0,0 +1,2 @@
+-- The reason for the pause that follows the interval.
+ALTER TABLE task_session_intervals ADD COLUMN pause_reason TEXT;
@@ -0,0 +1,2 @@
+-- Normalised timestamps can't be turned back into their original form.
+SELECT 1;
@@ -0,0 +1,37 @@
+-- Rewrite every stored instant as UTC "YYYY-MM-DD HH:MM:SS", the format
+-- CURRENT_TIMESTAMP uses. SQLite's datetime() converts values that carry an
+-- offset to UTC and drops fractional seconds; values it can't read are left
+-- alone.
+
+UPDATE tasks SET created_at = datetime(created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT NULL AND created_at != datetime(created_at);
+
+UPDATE task_sessions SET started_at = datetime(started_at)
+WHERE started_at IS NOT NULL AND datetime(started_at) IS NOT NULL AND started_at != datetime(started_at);
+
+UPDATE task_sessions SET ended_at = datetime(ended_at)
+WHERE ended_at IS NOT NULL AND datetime(ended_at) IS NOT NULL AND ended_at != datetime(ended_at);
+
+UPDATE task_session_intervals SET start_time = datetime(start_time)
+WHERE start_time IS NOT NULL AND datetime(start_time) IS NOT NULL AND start_time != datetime(start_time);
+
+UPDATE task_session_intervals SET end_time = datetime(end_time)
+WHERE end_time IS NOT NULL AND datetime(end_time) IS NOT NULL AND end_time != datetime(end_time);
+
+UPDATE session_tags SET created_at = datetime(created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT NULL AND created_at != datetime(created_at);
+
+UPDATE session_kpis SET created_at = datetime(created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT NULL AND created_at != datetime(created_at);
+
+UPDATE session_events SET occurred_at = datetime(occurred_at)
+WHERE occurred_at IS NOT NULL AND datetime(occurred_at) IS NOT NULL AND occurred_at != datetime(occurred_at);
+
+UPDATE session_events SET created_at = datetime(created_at)
+WHERE created_at IS NOT NULL AND datetime(created_at) IS NOT NULL AND created_at != datetime(created_at);
+
+UPDATE pomodoros SET started_at = datetime(started_at)
+WHERE started_at IS NOT NULL AND datetime(started_at) IS NOT NULL AND started_at != datetime(started_at);
+
+UPDATE pomodoros SET ended_at = datetime(ended_at)
+WHERE ended_at IS NOT NULL AND datetime(ended_at) IS NOT NULL AND ended_at != datetime(ended_at);
@@ -0,0 +1,2 @@
+-- How long the task is expected to take, in seconds. NULL means no estimate.
+ALTER TABLE tasks ADD COLUMN estimate_seconds INTEGER;
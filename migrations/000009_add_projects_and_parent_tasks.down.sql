@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_tasks_parent_id;
+DROP INDEX IF EXISTS idx_tasks_project_id;
+
+ALTER TABLE tasks DROP COLUMN parent_id;
+ALTER TABLE tasks DROP COLUMN project_id;
+
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,9 @@
+ALTER TABLE tasks ADD COLUMN status TEXT NOT NULL DEFAULT 'todo';
+ALTER TABLE tasks ADD COLUMN priority INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE tasks ADD COLUMN completed_at DATETIME;
+
+-- Tasks tracked before the backlog existed have been worked on.
+UPDATE tasks SET status = 'in_progress'
+WHERE id IN (SELECT task_id FROM task_sessions);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status, priority);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_session_notes_session_id;
+
+DROP TABLE IF EXISTS session_notes;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS labels (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  kind TEXT NOT NULL CHECK (kind IN ('tag', 'kpi')),
+  name TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (kind, name)
+);
+
+-- Every tag and KPI already in use starts out in the catalog.
+INSERT OR IGNORE INTO labels (kind, name)
+SELECT DISTINCT 'tag', tag FROM session_tags;
+
+INSERT OR IGNORE INTO labels (kind, name)
+SELECT DISTINCT 'kpi', kpi FROM session_kpis;
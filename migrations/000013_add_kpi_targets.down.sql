@@ -0,0 +1,2 @@
+ALTER TABLE labels DROP COLUMN target_value;
+ALTER TABLE labels DROP COLUMN target_metric;
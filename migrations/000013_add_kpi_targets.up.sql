@@ -0,0 +1,4 @@
+-- A KPI can set a weekly target: hours logged, sessions worked or commits
+-- made on sessions with the KPI.
+ALTER TABLE labels ADD COLUMN target_metric TEXT CHECK (target_metric IN ('hours', 'sessions', 'commits'));
+ALTER TABLE labels ADD COLUMN target_value REAL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_invoice_items_invoice_id;
+
+DROP TABLE IF EXISTS invoice_items;
+DROP TABLE IF EXISTS invoices;
+DROP TABLE IF EXISTS rates;
+
+ALTER TABLE task_sessions DROP COLUMN billable;
@@ -0,0 +1,40 @@
+ALTER TABLE task_sessions ADD COLUMN billable INTEGER NOT NULL DEFAULT 1;
+
+-- Hourly rates. The default rate has an empty name; the others name the
+-- client, project or tag they apply to.
+CREATE TABLE IF NOT EXISTS rates (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  scope TEXT NOT NULL CHECK (scope IN ('default', 'client', 'project', 'tag')),
+  name TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+  hourly_rate REAL NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  UNIQUE (scope, name)
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  number TEXT NOT NULL UNIQUE,
+  client TEXT NOT NULL,
+  period_start DATETIME NOT NULL,
+  period_end DATETIME NOT NULL,
+  currency TEXT NOT NULL,
+  hours REAL NOT NULL,
+  total REAL NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A session is invoiced at most once.
+CREATE TABLE IF NOT EXISTS invoice_items (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  invoice_id INTEGER NOT NULL,
+  session_id INTEGER NOT NULL UNIQUE,
+  description TEXT NOT NULL,
+  project TEXT NOT NULL DEFAULT '',
+  worked_on DATETIME NOT NULL,
+  hours REAL NOT NULL,
+  rate REAL NOT NULL,
+  amount REAL NOT NULL,
+  FOREIGN KEY (invoice_id) REFERENCES invoices(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_invoice_items_invoice_id ON invoice_items(invoice_id);
@@ -0,0 +1,3 @@
+ALTER TABLE commits DROP COLUMN deletions;
+ALTER TABLE commits DROP COLUMN insertions;
+ALTER TABLE commits DROP COLUMN files_changed;
@@ -0,0 +1,5 @@
+-- Diff statistics of each commit, as git show --numstat reports them.
+-- Commits recorded before this are filled in by the next sync.
+ALTER TABLE commits ADD COLUMN files_changed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE commits ADD COLUMN insertions INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE commits ADD COLUMN deletions INTEGER NOT NULL DEFAULT 0;
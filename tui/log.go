@@ -57,7 +57,7 @@ func (m LogModel) View() string {
 			b.WriteString("💾  Standalone Commits (Not Linked to Any Session)\n\n")
 			for _, s := range log.Sessions {
 				for _, c := range s.Commits {
-					b.WriteString(fmt.Sprintf("✔ [%s] %s\n", c.Date, c.Message))
+					b.WriteString(fmt.Sprintf("✔ [%s] %s%s\n", c.Date, c.Message, fmtDiffStat(c.DiffStat)))
 				}
 			}
 			b.WriteString("\n")
@@ -68,22 +68,42 @@ func (m LogModel) View() string {
 		b.WriteString(fmt.Sprintf("[%s]\n", log.Date))
 
 		for _, s := range log.Sessions {
-			start := s.StartedAt.Format("15:04")
+			start := s.StartedAt.In(data.Location()).Format("15:04")
 
 			end := "ongoing"
 			if !s.EndedAt.IsZero() {
-				end = s.EndedAt.Format("15:04")
+				end = s.EndedAt.In(data.Location()).Format("15:04")
 			}
 
 			duration := fmtDuration(s.TotalTime)
 
-			b.WriteString(fmt.Sprintf("🕒 %s - %s | Task: \"%s\" | ⏱ %s\n",
-				start, end, s.Task, duration))
+			b.WriteString(fmt.Sprintf("🕒 %s - %s | Task: \"%s\" [%s] | ⏱ %s\n",
+				start, end, s.Task, strings.ReplaceAll(s.TaskStatus, "_", " "), duration))
 
-			if len(s.Commits) > 0 {
+			if s.Estimate > 0 {
+				if left := s.Estimate - s.TaskTime; left >= 0 {
+					b.WriteString(fmt.Sprintf("  🎯 %s of %s estimate used, %s left\n",
+						fmtDuration(s.TaskTime), fmtDuration(s.Estimate), fmtDuration(left)))
+				} else {
+					b.WriteString(fmt.Sprintf("  🎯 %s of %s estimate used, %s over\n",
+						fmtDuration(s.TaskTime), fmtDuration(s.Estimate), fmtDuration(-left)))
+				}
+			}
+
+			if s.Churn.Files > 0 {
+				b.WriteString(fmt.Sprintf("  📈 Churn: %s\n", s.Churn))
+			}
+
+			switch {
+			case len(s.Notes) > 0:
+				b.WriteString("  - Timeline:\n")
+				for _, e := range timeline(s) {
+					b.WriteString(fmt.Sprintf("    %s %s\n", e.at, e.text))
+				}
+			case len(s.Commits) > 0:
 				b.WriteString("  - Commits:\n")
 				for _, c := range s.Commits {
-					b.WriteString(fmt.Sprintf("    ✔ %s\n", c.Message))
+					b.WriteString(fmt.Sprintf("    ✔ %s%s\n", c.Message, fmtDiffStat(c.DiffStat)))
 				}
 			}
 			b.WriteString("\n")
@@ -94,6 +114,50 @@ func (m LogModel) View() string {
 	return b.String()
 }
 
+type timelineEntry struct {
+	when time.Time
+	at   string
+	text string
+}
+
+// timeline merges a session's commits and notes in time order. Commits whose
+// date can't be read keep their place at the start.
+func timeline(s data.LogSession) []timelineEntry {
+	entries := make([]timelineEntry, 0, len(s.Commits)+len(s.Notes))
+
+	for _, c := range s.Commits {
+		e := timelineEntry{at: "     ", text: "✔ " + c.Message + fmtDiffStat(c.DiffStat)}
+		if date, err := data.ParseCommitDate(c.Date); err == nil {
+			e.when = date
+			e.at = date.In(data.Location()).Format("15:04")
+		}
+		entries = append(entries, e)
+	}
+
+	for _, n := range s.Notes {
+		entries = append(entries, timelineEntry{
+			when: n.NotedAt,
+			at:   n.NotedAt.In(data.Location()).Format("15:04"),
+			text: "📝 " + n.Body,
+		})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].when.Before(entries[j].when)
+	})
+
+	return entries
+}
+
+// fmtDiffStat is a commit's " (+12 -3)" suffix, or empty for commits
+// recorded without diff stats.
+func fmtDiffStat(d data.DiffStat) string {
+	if d.Files == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (+%d -%d)", d.Insertions, d.Deletions)
+}
+
 func fmtDuration(d time.Duration) string {
 	h := int(d.Hours())
 	m := int(d.Minutes()) % 60
@@ -8,37 +8,79 @@ import (
 	"github.com/tormgibbs/worklogger/data"
 )
 
-// type Session struct {
-// 	ID          string
-// 	Description string
-// }
+const TaskOptionNew = "+ Create a new task"
 
 type TaskSelectModel struct {
 	Title    string
-	Sessions []*data.DetailedTaskSession
+	Options  []string
 	Cursor   int
-	Selected *data.DetailedTaskSession
+	Selected int
 }
 
 func RunTaskSelectUI(title string, sessions []*data.DetailedTaskSession) (*data.DetailedTaskSession, error) {
-	p := tea.NewProgram(NewTaskSelectModel(title, sessions))
-	m, err := p.Run()
+	options := make([]string, len(sessions))
+	for i, session := range sessions {
+		options[i] = session.Task.Description
+	}
+
+	index, err := runTaskSelect(title, options)
 	if err != nil {
 		return nil, err
 	}
 
-	selected := m.(TaskSelectModel).Selected
-	if selected == nil {
+	if index < 0 {
 		return nil, fmt.Errorf("no task selected")
 	}
 
-	return selected, nil
+	return sessions[index], nil
 }
 
-func NewTaskSelectModel(title string, sessions []*data.DetailedTaskSession) TaskSelectModel {
+// RunTaskPickUI lets the user choose one of tasks or opt to create a new
+// one. It returns a nil task when "create new" is picked.
+func RunTaskPickUI(title string, tasks []*data.Task) (*data.Task, error) {
+	options := make([]string, 0, len(tasks)+1)
+	for _, task := range tasks {
+		options = append(options, fmt.Sprintf("%s (#%d)", task.Description, task.ID))
+	}
+	options = append(options, TaskOptionNew)
+
+	index, err := runTaskSelect(title, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 {
+		return nil, fmt.Errorf("no task selected")
+	}
+
+	if index == len(tasks) {
+		return nil, nil
+	}
+
+	return tasks[index], nil
+}
+
+// RunChoiceUI shows a list of options and returns the index picked, or -1
+// if the user quit without choosing.
+func RunChoiceUI(title string, options []string) (int, error) {
+	return runTaskSelect(title, options)
+}
+
+func runTaskSelect(title string, options []string) (int, error) {
+	p := tea.NewProgram(NewTaskSelectModel(title, options))
+	m, err := p.Run()
+	if err != nil {
+		return -1, err
+	}
+
+	return m.(TaskSelectModel).Selected, nil
+}
+
+func NewTaskSelectModel(title string, options []string) TaskSelectModel {
 	return TaskSelectModel{
 		Title:    title,
-		Sessions: sessions,
+		Options:  options,
+		Selected: -1,
 	}
 }
 
@@ -55,11 +97,13 @@ func (m TaskSelectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Cursor--
 			}
 		case "down", "j":
-			if m.Cursor < len(m.Sessions)-1 {
+			if m.Cursor < len(m.Options)-1 {
 				m.Cursor++
 			}
 		case "enter":
-			m.Selected = m.Sessions[m.Cursor]
+			if len(m.Options) > 0 {
+				m.Selected = m.Cursor
+			}
 			return m, tea.Quit
 		case "q", "ctrl+c":
 			return m, tea.Quit
@@ -74,19 +118,19 @@ func (m TaskSelectModel) View() string {
 	b.WriteString(taskTitleStyle.Render(fmt.Sprintf("\n%s\n", m.Title)))
 	b.WriteString("\n")
 
-	for i, session := range m.Sessions {
+	for i, option := range m.Options {
 		cursor := " "
-		display := session.Task.Description
+		display := option
 
 		if m.Cursor == i {
 			cursor = taskCursorStyle.Render("›")
-			display = taskActiveStyle.Render(session.Task.Description)
+			display = taskActiveStyle.Render(option)
 		}
 
 		b.WriteString(fmt.Sprintf("%s %s\n", cursor, display))
 	}
 
-	if m.Selected == nil {
+	if m.Selected < 0 {
 		b.WriteString("\n")
 		b.WriteString(taskHintStyle.Render("↑/↓: navigate • enter: select • q: quit") + "\n")
 	}
@@ -5,6 +5,9 @@ export interface Session {
   endTime: string
   duration: string
   status: 'in_progress' | 'paused' | 'ended'
+  files_changed?: number
+  insertions?: number
+  deletions?: number
 }
 
 export const sessions: Session[] = [
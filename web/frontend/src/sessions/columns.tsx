@@ -41,6 +41,24 @@ export const columns: ColumnDef<Session>[] = [
     accessorKey: 'duration',
     header: 'Duration',
   },
+  {
+    id: 'changes',
+    header: 'Changes',
+    cell: ({ row }) => {
+      const { files_changed, insertions = 0, deletions = 0 } = row.original
+
+      if (!files_changed) {
+        return '-'
+      }
+
+      return (
+        <span title={`${files_changed} file${files_changed === 1 ? '' : 's'} changed`}>
+          <span className="text-green-700">+{insertions}</span>{' '}
+          <span className="text-red-700">−{deletions}</span>
+        </span>
+      )
+    },
+  },
   {
     accessorKey: 'status',
     header: 'Status',
@@ -1,11 +1,14 @@
 package server
 
 import (
+	"bytes"
 	"database/sql"
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"github.com/julienschmidt/httprouter"
 	"github.com/tormgibbs/worklogger/data"
 )
 
@@ -53,91 +56,95 @@ func (h *Handler) getMonthlyStats(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, stats)
 }
 
-func (h *Handler) getSessions(w http.ResponseWriter, r *http.Request) {
-	sessions, err := data.GetSessions(h.DB)
+func (h *Handler) getProjectStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := data.GetProjectTotals(h.DB)
 	if err != nil {
 		fmt.Println(err)
-		http.Error(w, "Failed to get sessions", http.StatusInternalServerError)
+		http.Error(w, "Failed to get project stats", http.StatusInternalServerError)
 		return
 	}
-	writeJSON(w, http.StatusOK, sessions)
+	writeJSON(w, http.StatusOK, stats)
 }
 
-func (h *Handler) exportAllDataCSV(w http.ResponseWriter, r *http.Request) {
-	summary, err := data.GetSummaryStats(h.DB)
+func (h *Handler) getParentStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := data.GetParentTotals(h.DB)
 	if err != nil {
-		http.Error(w, "Failed to get summary", http.StatusInternalServerError)
+		fmt.Println(err)
+		http.Error(w, "Failed to get parent task stats", http.StatusInternalServerError)
 		return
 	}
+	writeJSON(w, http.StatusOK, stats)
+}
 
-	daily, err := data.GetDailyStats(h.DB)
+func (h *Handler) getEstimates(w http.ResponseWriter, r *http.Request) {
+	report, err := data.GetEstimateReport(h.DB)
 	if err != nil {
-		http.Error(w, "Failed to get daily stats", http.StatusInternalServerError)
+		fmt.Println(err)
+		http.Error(w, "Failed to get estimates", http.StatusInternalServerError)
 		return
 	}
+	writeJSON(w, http.StatusOK, report)
+}
 
-	weekly, err := data.GetWeeklyStats(h.DB)
-	if err != nil {
-		http.Error(w, "Failed to get weekly stats", http.StatusInternalServerError)
-		return
+func (h *Handler) getKPIs(w http.ResponseWriter, r *http.Request) {
+	weeks := 4
+	if value := r.URL.Query().Get("weeks"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 52 {
+			http.Error(w, "weeks must be between 1 and 52", http.StatusBadRequest)
+			return
+		}
+		weeks = n
 	}
 
-	monthly, err := data.GetMonthlyStats(h.DB)
+	report, err := data.GetKPIReport(h.DB, weeks)
 	if err != nil {
-		http.Error(w, "Failed to get monthly stats", http.StatusInternalServerError)
+		fmt.Println(err)
+		http.Error(w, "Failed to get KPI report", http.StatusInternalServerError)
 		return
 	}
+	writeJSON(w, http.StatusOK, report)
+}
 
+func (h *Handler) getSessions(w http.ResponseWriter, r *http.Request) {
 	sessions, err := data.GetSessions(h.DB)
 	if err != nil {
+		fmt.Println(err)
 		http.Error(w, "Failed to get sessions", http.StatusInternalServerError)
 		return
 	}
+	writeJSON(w, http.StatusOK, sessions)
+}
 
-	w.Header().Set("Content-Disposition", "attachment; filename=export.csv")
-	w.Header().Set("Content-Type", "text/csv")
-	writer := csv.NewWriter(w)
-	defer writer.Flush()
-
-	writer.Write([]string{"Section", "Metric", "Value", "Change"})
-	writer.Write([]string{"Summary", "Today's Hours", fmt.Sprintf("%v", summary.TodayHours.Value), fmt.Sprintf("%v", summary.TodayHours.Change)})
-	writer.Write([]string{"Summary", "Week Hours", fmt.Sprintf("%v", summary.WeekHours.Value), fmt.Sprintf("%v", summary.WeekHours.Change)})
-	writer.Write([]string{"Summary", "Sessions Today", fmt.Sprintf("%v", summary.SessionsToday.Value), fmt.Sprintf("%v", summary.SessionsToday.Change)})
-	writer.Write([]string{"Summary", "Productivity Score", fmt.Sprintf("%v", summary.ProductivityScore.Value), fmt.Sprintf("%v", summary.ProductivityScore.Change)})
-
-	writer.Write([]string{}) // empty row
-	writer.Write([]string{"Section", "Date", "Hours", "Sessions"})
-	for _, d := range daily {
-		writer.Write([]string{"Daily", d.Date, fmt.Sprintf("%.2f", d.Hours), fmt.Sprintf("%d", d.Sessions)})
+func (h *Handler) getSession(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(httprouter.ParamsFromContext(r.Context()).ByName("id"))
+	if err != nil || id < 1 {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
 	}
 
-	writer.Write([]string{})
-	writer.Write([]string{"Section", "Week Start", "Hours", "Sessions"})
-	for _, wStat := range weekly {
-		writer.Write([]string{"Weekly", wStat.Start, fmt.Sprintf("%.2f", wStat.Hours), fmt.Sprintf("%d", wStat.Sessions)})
+	session, err := data.GetSession(h.DB, id)
+	if errors.Is(err, data.ErrRecordNotFound) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
 	}
-
-	writer.Write([]string{})
-	writer.Write([]string{"Section", "Month", "Hours", "Sessions"})
-	for _, m := range monthly {
-		writer.Write([]string{"Monthly", m.Month, fmt.Sprintf("%.2f", m.Hours), fmt.Sprintf("%d", m.Sessions)})
+	if err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to get session", http.StatusInternalServerError)
+		return
 	}
+	writeJSON(w, http.StatusOK, session)
+}
 
-	writer.Write([]string{})
-	writer.Write([]string{"Section", "Task", "Start Time", "End Time", "Duration", "Status"})
-	for _, s := range sessions {
-		endTime := ""
-		if s.EndTime != nil {
-			endTime = *s.EndTime
-		}
-
-		writer.Write([]string{
-			"Session",
-			s.Task,
-			s.StartTime,
-			endTime,
-			s.Duration,
-			s.Status,
-		})
+func (h *Handler) exportAllDataCSV(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := data.WriteCSV(h.DB, &buf); err != nil {
+		fmt.Println(err)
+		http.Error(w, "Failed to export data", http.StatusInternalServerError)
+		return
 	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=export.csv")
+	w.Header().Set("Content-Type", "text/csv")
+	w.Write(buf.Bytes())
 }
@@ -4,18 +4,25 @@ import (
 	"database/sql"
 	"log"
 	"net/http"
+	"strings"
 )
 
-var Addr = "http://localhost:3001"
+// URL is where the studio served on addr can be opened in a browser.
+func URL(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "http://localhost" + addr
+	}
+	return "http://" + addr
+}
 
-func Serve(db *sql.DB) {
+func Serve(db *sql.DB, addr string) {
 	handler := &Handler{DB: db}
 
 	server := &http.Server{
-		Addr:    ":3001",
+		Addr:    addr,
 		Handler: routes(handler),
 	}
 
-	log.Println("Server running on :3001")
+	log.Printf("Server running on %s\n", addr)
 	log.Fatal(server.ListenAndServe())
 }
@@ -1,6 +1,10 @@
 package server
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/tormgibbs/worklogger/data"
+)
 
 func enableCORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -8,3 +12,13 @@ func enableCORS(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// reportRounding tells API clients how the hours in the response were
+// rounded, "none" if they are exact. Only handlers whose reports apply the
+// rounding are wrapped; estimates and KPI progress stay exact.
+func reportRounding(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Worklogger-Rounding", data.RoundingLabel())
+		next(w, r)
+	}
+}
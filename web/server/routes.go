@@ -9,12 +9,17 @@ import (
 func routes(h *Handler) http.Handler {
 	router := httprouter.New()
 
-	router.HandlerFunc(http.MethodGet, "/api/summary", h.getSummary)
-	router.HandlerFunc(http.MethodGet, "/api/stats/daily", h.getDailyStats)
-	router.HandlerFunc(http.MethodGet, "/api/stats/weekly", h.getWeeklyStats)
-	router.HandlerFunc(http.MethodGet, "/api/stats/monthly", h.getMonthlyStats)
-	router.HandlerFunc(http.MethodGet, "/api/sessions", h.getSessions)
-	router.HandlerFunc(http.MethodGet, "/api/export.csv", h.exportAllDataCSV)
+	router.HandlerFunc(http.MethodGet, "/api/summary", reportRounding(h.getSummary))
+	router.HandlerFunc(http.MethodGet, "/api/stats/daily", reportRounding(h.getDailyStats))
+	router.HandlerFunc(http.MethodGet, "/api/stats/weekly", reportRounding(h.getWeeklyStats))
+	router.HandlerFunc(http.MethodGet, "/api/stats/monthly", reportRounding(h.getMonthlyStats))
+	router.HandlerFunc(http.MethodGet, "/api/stats/projects", reportRounding(h.getProjectStats))
+	router.HandlerFunc(http.MethodGet, "/api/stats/parents", reportRounding(h.getParentStats))
+	router.HandlerFunc(http.MethodGet, "/api/sessions", reportRounding(h.getSessions))
+	router.HandlerFunc(http.MethodGet, "/api/sessions/:id", reportRounding(h.getSession))
+	router.HandlerFunc(http.MethodGet, "/api/estimates", h.getEstimates)
+	router.HandlerFunc(http.MethodGet, "/api/kpis", h.getKPIs)
+	router.HandlerFunc(http.MethodGet, "/api/export.csv", reportRounding(h.exportAllDataCSV))
 
 	fsHandler := http.FileServer(frontendFS)
 	router.Handler(http.MethodGet, "/", fsHandler)